@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"encoding/gob"
 	"fmt"
+	"time"
+
 	"github.com/cespare/xxhash"
+	"github.com/datasweet/cast"
 	"github.com/pkg/errors"
 	"github.com/xinzf/datatable/serie"
 )
@@ -19,6 +22,24 @@ type GroupBy struct {
 	Keyer func(row Row) (interface{}, bool)
 }
 
+// TimeKeyer creates a keyer on the part of the time in column {col}, ie serie.Year
+// Made for Time columns, the values of other columns are parsed with the formats, as serie.Dt.
+// The rows without time are grouped with a nil key.
+func TimeKeyer(col string, part serie.TimePart, formats ...string) func(row Row) (interface{}, bool) {
+	return func(row Row) (interface{}, bool) {
+		switch v := row[col].(type) {
+		case nil:
+		case time.Time:
+			return part.Of(v), true
+		default:
+			if tm, ok := cast.AsTime(v, formats...); ok {
+				return part.Of(tm), true
+			}
+		}
+		return nil, false
+	}
+}
+
 // AggregationType defines the avalaible aggregation
 type AggregationType uint8
 
@@ -72,6 +93,20 @@ func (a AggregationType) String() string {
 	}
 }
 
+// columnType returns the type of the column aggregated from a column of type src
+func (a AggregationType) columnType(src ColumnType) ColumnType {
+	switch a {
+	case Count, CountDistinct:
+		return Int64
+	case GroupConcat:
+		return Raw
+	case GroupAny:
+		return src
+	default:
+		return Float64
+	}
+}
+
 // AggregateBy defines the aggregation
 type AggregateBy struct {
 	Type  AggregationType
@@ -89,21 +124,20 @@ func (dt *DataTable) GroupBy(by ...GroupBy) (*Groups, error) {
 	gindex := make(map[uint64]int)
 
 	for pos := 0; pos < dt.nrows; pos++ {
-		row := dt.Row(pos)
-		buf := bytes.NewBuffer(nil)
-		enc := gob.NewEncoder(buf)
-
+		row, err := dt.RowE(pos)
+		if err != nil {
+			return nil, err
+		}
 		buckets := make([]interface{}, len(by))
 
 		for i, k := range by {
 			k := &k
 			if v, ok := k.Keyer(row); ok {
 				buckets[i] = v
-				enc.Encode(v)
 			}
 		}
 
-		hash := xxhash.Sum64(buf.Bytes())
+		hash := hashKeys(buckets)
 
 		if at, ok := gindex[hash]; ok {
 			groups[at].Rows = append(groups[at].Rows, pos)
@@ -119,6 +153,20 @@ func (dt *DataTable) GroupBy(by ...GroupBy) (*Groups, error) {
 	return &Groups{dt: dt, groups: groups, by: by}, nil
 }
 
+// hashKeys computes the hash code of the group keys
+func hashKeys(keys []interface{}) uint64 {
+	buf := bytes.NewBuffer(nil)
+	enc := gob.NewEncoder(buf)
+	for _, k := range keys {
+		// gob can't encode nil: flag the presence of the key
+		enc.Encode(k != nil)
+		if k != nil {
+			enc.Encode(k)
+		}
+	}
+	return xxhash.Sum64(buf.Bytes())
+}
+
 // Aggregate aggregates some field
 func (dt *DataTable) Aggregate(by ...AggregateBy) (*DataTable, error) {
 	g := &Groups{
@@ -146,12 +194,19 @@ type group struct {
 
 // Aggregate our groups
 func (g *Groups) Aggregate(aggs ...AggregateBy) (*DataTable, error) {
+	out, _, err := g.aggregate(aggs...)
+	return out, err
+}
+
+// aggregate aggregates the groups, and returns the group of each row of the summary
+// A group is missing if its row can't be appended.
+func (g *Groups) aggregate(aggs ...AggregateBy) (*DataTable, []*group, error) {
 	if g == nil {
-		return nil, ErrNoGroups
+		return nil, nil, ErrNoGroups
 	}
 
 	if g.dt == nil {
-		return nil, ErrNilDatatable
+		return nil, nil, ErrNilDatatable
 	}
 
 	// check cols
@@ -160,13 +215,13 @@ func (g *Groups) Aggregate(aggs ...AggregateBy) (*DataTable, error) {
 		col := g.dt.Column(agg.Field)
 		if col == nil {
 			err := errors.Errorf("column '%s' not found", agg.Field)
-			return nil, errors.Wrap(err, ErrColumnNotFound.Error())
+			return nil, nil, errors.Wrap(err, ErrColumnNotFound.Error())
 		}
 		switch agg.Type {
 		case Avg, Count, CountDistinct, Cusum, Max, Min, Median, Stddev, Sum, Variance, GroupConcat, GroupAny:
 			series[agg.Field] = col.(*column).serie
 		default:
-			return nil, ErrUnknownAgg
+			return nil, nil, ErrUnknownAgg
 		}
 	}
 
@@ -175,20 +230,25 @@ func (g *Groups) Aggregate(aggs ...AggregateBy) (*DataTable, error) {
 	// create columns
 	for _, by := range g.by {
 		typ := by.Type
-		col := g.dt.Column(by.Name).Clone()
+		label := by.Name
+		if col := g.dt.Column(by.Name); col != nil {
+			if len(typ) == 0 {
+				typ = col.Type()
+			}
+			label = col.Label()
+		}
 		if len(typ) == 0 {
-			//typ = Raw
-			typ = col.Type()
+			typ = Raw
 		}
 
-		if err := out.AddColumn(col.Name(), typ, func(opts *ColumnOptions) {
-			opts.Label = col.Label()
+		if err := out.AddColumn(by.Name, typ, func(opts *ColumnOptions) {
+			opts.Label = label
 		}); err != nil {
 			//if err := out.addColumn(col.(*column)); err != nil {
 			//}
 			//if err := out.AddColumn(by.Name, typ); err != nil {
 			err = errors.Wrapf(err, "can't add column '%s'", by.Name)
-			return nil, errors.Wrap(err, ErrCantAddColumn.Error())
+			return nil, nil, errors.Wrap(err, ErrCantAddColumn.Error())
 		}
 	}
 	for _, agg := range aggs {
@@ -201,16 +261,7 @@ func (g *Groups) Aggregate(aggs ...AggregateBy) (*DataTable, error) {
 
 		col := g.dt.Column(agg.Field).Clone()
 
-		typ := Float64
-		switch agg.Type {
-		case Count, CountDistinct:
-			typ = Int64
-		case GroupConcat:
-			typ = Raw
-		case GroupAny:
-			typ = col.Type()
-		default:
-		}
+		typ := agg.Type.columnType(col.Type())
 		if err := out.AddColumn(name, typ, func(opts *ColumnOptions) {
 			opts.Label = fmt.Sprintf("%s_%s", agg.Type, col.Label())
 		}); err != nil {
@@ -219,11 +270,12 @@ func (g *Groups) Aggregate(aggs ...AggregateBy) (*DataTable, error) {
 			//	}
 			//if err := out.AddColumn(name, typ); err != nil {
 			err = errors.Wrapf(err, "can't add column '%s'", name)
-			return nil, errors.Wrap(err, ErrCantAddColumn.Error())
+			return nil, nil, errors.Wrap(err, ErrCantAddColumn.Error())
 		}
 	}
 
 	// aggregate the series
+	rows := make([]*group, 0, len(g.groups))
 	for _, group := range g.groups {
 		values := make([]interface{}, 0, len(group.Buckets)+len(aggs))
 		values = append(values, group.Buckets...)
@@ -262,8 +314,10 @@ func (g *Groups) Aggregate(aggs ...AggregateBy) (*DataTable, error) {
 				values = append(values, serie.GroupAny())
 			}
 		}
-		out.AppendRow(values...)
+		if err := out.AppendRow(values...); err == nil {
+			rows = append(rows, group)
+		}
 	}
 
-	return out, nil
+	return out, rows, nil
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/xinzf/datatable"
+	"github.com/xinzf/datatable/serie"
 )
 
 func TestAggregate(t *testing.T) {
@@ -60,3 +61,44 @@ func TestAggregate(t *testing.T) {
 	assert.NoError(t, err)
 	fmt.Println(gdt)
 }
+
+func TestTimeKeyer(t *testing.T) {
+	dt := datatable.New("test")
+	dt.AddColumn("date", datatable.Time, datatable.Values("2013-01-23", "2013-02-14", nil, "2014-03-02"))
+	dt.AddColumn("amount", datatable.Int, datatable.Values(1, 2, 3, 4))
+
+	groups, err := dt.GroupBy(datatable.GroupBy{
+		Name:  "year",
+		Type:  datatable.Int,
+		Keyer: datatable.TimeKeyer("date", serie.Year),
+	})
+	assert.NoError(t, err)
+	out, err := groups.Aggregate(datatable.AggregateBy{Type: datatable.Sum, Field: "amount"})
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"year", "sum_amount",
+		2013, 3.0,
+		nil, 3.0,
+		2014, 4.0,
+	)
+
+	// the values of a string column are parsed with the formats
+	dt = datatable.New("test")
+	dt.AddColumn("date", datatable.String, datatable.Values("23/01/2013", "14/02/2013", "bad", "02/03/2014"))
+	dt.AddColumn("amount", datatable.Int, datatable.Values(1, 2, 3, 4))
+
+	groups, err = dt.GroupBy(datatable.GroupBy{
+		Name:  "year",
+		Type:  datatable.Int,
+		Keyer: datatable.TimeKeyer("date", serie.Year, "02/01/2006"),
+	})
+	assert.NoError(t, err)
+	out, err = groups.Aggregate(datatable.AggregateBy{Type: datatable.Sum, Field: "amount"})
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"year", "sum_amount",
+		2013, 3.0,
+		nil, 3.0,
+		2014, 4.0,
+	)
+}
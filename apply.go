@@ -0,0 +1,176 @@
+package datatable
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ApplyOptions describes options to apply a function on rows
+type ApplyOptions struct {
+	Workers int
+}
+
+// ApplyOption sets apply options
+type ApplyOption func(opts *ApplyOptions)
+
+// ApplyWorkers calls the function with {n} goroutines
+// Useful for expensive functions, which must be safe for concurrent use.
+func ApplyWorkers(n int) ApplyOption {
+	return func(opts *ApplyOptions) {
+		opts.Workers = n
+	}
+}
+
+// Mutate creates or replaces the column {name} with the result of {fn} on each row.
+// The row contains all columns, hidden included.
+// A replaced column keeps its label, attributes and visibility, but loses its expression.
+// A panic in {fn} is returned as an error, the datatable is not modified.
+func (t *DataTable) Mutate(name string, ctyp ColumnType, fn func(row Row) interface{}, opt ...ApplyOption) error {
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		return ErrNilColumnName
+	}
+	if fn == nil {
+		return ErrNilFunction
+	}
+
+	var options ApplyOptions
+	for _, o := range opt {
+		o(&options)
+	}
+
+	if err := t.evaluateExpressions(); err != nil {
+		return err
+	}
+
+	values := make([]interface{}, t.nrows)
+	err := parallelize(t.nrows, options.Workers, func(i int) {
+		r := make(Row, len(t.cols))
+		for _, col := range t.cols {
+			r[col.name] = col.serie.Get(i)
+		}
+		values[i] = fn(r)
+	})
+	if err != nil {
+		return err
+	}
+
+	sr, err := newColumnSerie(ctyp, ColumnOptions{Values: values})
+	if err != nil {
+		return errors.Wrap(err, ErrCreateSerie.Error())
+	}
+
+	pos := t.ColumnIndex(name)
+	if pos < 0 {
+		return t.addColumn(&column{
+			name:  name,
+			typ:   ctyp,
+			serie: sr,
+		})
+	}
+
+	col := t.cols[pos]
+	col.typ = ctyp
+	col.serie = sr
+	col.formulae = ""
+	col.expr = nil
+	col.deps = nil
+	col.aggregated = false
+	t.markColumn(col.name)
+	return nil
+}
+
+// ApplyColumn replaces the values of the column {name} with the result of {fn} on each value.
+// The results are converted to the column type, a computed column can't be modified.
+// A panic in {fn} is returned as an error, the column is not modified.
+func (t *DataTable) ApplyColumn(name string, fn func(v interface{}) interface{}, opt ...ApplyOption) error {
+	if fn == nil {
+		return ErrNilFunction
+	}
+	col, err := t.fillableColumn(name)
+	if err != nil {
+		return err
+	}
+
+	var options ApplyOptions
+	for _, o := range opt {
+		o(&options)
+	}
+
+	values := make([]interface{}, t.nrows)
+	err = parallelize(t.nrows, options.Workers, func(i int) {
+		values[i] = fn(col.serie.Get(i))
+	})
+	if err != nil {
+		return err
+	}
+
+	sr := col.serie.EmptyCopy()
+	sr.Append(values...)
+	col.serie = sr
+	t.markColumn(col.name)
+	return nil
+}
+
+// parallelize calls {fn} for each index in [0, n) with {workers} goroutines
+// A panic in {fn} is recovered and returned as an error.
+func parallelize(n, workers int, fn func(i int)) error {
+	call := func(i int) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errors.Wrapf(errors.Errorf("%v", r), "at row %d", i)
+				err = errors.Wrap(err, ErrApplyFunction.Error())
+			}
+		}()
+		fn(i)
+		return nil
+	}
+
+	if workers <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			if err := call(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if workers > n {
+		workers = n
+	}
+
+	var (
+		wg      sync.WaitGroup
+		once    sync.Once
+		failure error
+		indexes = make(chan int, workers)
+		done    = make(chan struct{})
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := call(i); err != nil {
+					once.Do(func() {
+						failure = err
+						close(done)
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-done:
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	return failure
+}
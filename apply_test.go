@@ -0,0 +1,84 @@
+package datatable_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func TestMutate(t *testing.T) {
+	tb := datatable.New("test")
+	tb.AddColumn("champ", datatable.String, datatable.Values("Malzahar", "Xerath", "Teemo"))
+	tb.AddColumn("win", datatable.Int, datatable.Values(10, 20, 666))
+	tb.AddColumn("double", datatable.Int, datatable.Expr("`win` * 2"))
+
+	err := tb.Mutate("label", datatable.String, func(row datatable.Row) interface{} {
+		return strings.ToLower(row.Get("champ").(string)) + "-" + fmt.Sprint(row.Get("double"))
+	})
+	assert.NoError(t, err)
+	checkTable(t, tb,
+		"champ", "win", "double", "label",
+		"Malzahar", 10, 20, "malzahar-20",
+		"Xerath", 20, 40, "xerath-40",
+		"Teemo", 666, 1332, "teemo-1332",
+	)
+
+	// replaces a column, with workers
+	err = tb.Mutate("win", datatable.Float64, func(row datatable.Row) interface{} {
+		return float64(row.Get("win").(int)) / 2
+	}, datatable.ApplyWorkers(4))
+	assert.NoError(t, err)
+	checkTable(t, tb,
+		"champ", "win", "double", "label",
+		"Malzahar", 5.0, 10, "malzahar-20",
+		"Xerath", 10.0, 20, "xerath-40",
+		"Teemo", 333.0, 666, "teemo-1332",
+	)
+	assert.Equal(t, datatable.Float64, tb.Column("win").Type())
+
+	// a panic is returned as an error
+	for _, workers := range []int{1, 4} {
+		err = tb.Mutate("oops", datatable.Int, func(row datatable.Row) interface{} {
+			return row.Get("double").(string)
+		}, datatable.ApplyWorkers(workers))
+		assert.Error(t, err)
+		assert.Nil(t, tb.Column("oops"))
+	}
+
+	assert.Error(t, tb.Mutate("", datatable.Int, func(datatable.Row) interface{} { return 1 }))
+	assert.Error(t, tb.Mutate("x", datatable.Int, nil))
+	assert.Error(t, tb.Mutate("x", datatable.ColumnType("unknown"), func(datatable.Row) interface{} { return 1 }))
+}
+
+func TestApplyColumn(t *testing.T) {
+	tb := datatable.New("test")
+	tb.AddColumn("champ", datatable.String, datatable.Values("Malzahar", nil, "Teemo"))
+	tb.AddColumn("upper", datatable.String, datatable.Expr("upper(`champ`)"))
+
+	err := tb.ApplyColumn("champ", func(v interface{}) interface{} {
+		if v == nil {
+			return "unknown"
+		}
+		return v.(string) + "!"
+	}, datatable.ApplyWorkers(2))
+	assert.NoError(t, err)
+	checkTable(t, tb,
+		"champ", "upper",
+		"Malzahar!", "MALZAHAR!",
+		"unknown", "UNKNOWN",
+		"Teemo!", "TEEMO!",
+	)
+
+	err = tb.ApplyColumn("champ", func(v interface{}) interface{} {
+		panic("oops")
+	}, datatable.ApplyWorkers(2))
+	assert.Error(t, err)
+	assert.Equal(t, "unknown", tb.Column("champ").Serie().Get(1))
+
+	assert.Error(t, tb.ApplyColumn("upper", func(v interface{}) interface{} { return v }))
+	assert.Error(t, tb.ApplyColumn("unknown", func(v interface{}) interface{} { return v }))
+	assert.Error(t, tb.ApplyColumn("champ", nil))
+}
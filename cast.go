@@ -0,0 +1,88 @@
+package datatable
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CastOptions describes options to cast a column
+type CastOptions struct {
+	Strict      bool
+	TimeFormats []string
+}
+
+// CastOption sets cast options
+type CastOption func(opts *CastOptions)
+
+// CastStrict fails if a value can't be converted
+// By default, the value is set to nil.
+func CastStrict(v bool) CastOption {
+	return func(opts *CastOptions) {
+		opts.Strict = v
+	}
+}
+
+// CastTimeFormats sets the valid time formats.
+// <!> Only to cast to a Time column
+func CastTimeFormats(v ...string) CastOption {
+	return func(opts *CastOptions) {
+		opts.TimeFormats = append(opts.TimeFormats, v...)
+	}
+}
+
+// maxCastErrorRows is the max number of rows reported in a cast error
+const maxCastErrorRows = 10
+
+// CastColumn converts the values of a column to a new type
+// Label, attributes and visibility are kept.
+// A value that can't be converted is set to nil, or in strict mode,
+// the error reports the failed rows and the column is not modified.
+func (t *DataTable) CastColumn(name string, ctyp ColumnType, opt ...CastOption) error {
+	pos := t.ColumnIndex(name)
+	if pos < 0 {
+		err := errors.Errorf("column '%s' not found", name)
+		return errors.Wrap(err, ErrColumnNotFound.Error())
+	}
+
+	var options CastOptions
+	for _, o := range opt {
+		o(&options)
+	}
+
+	if err := t.evaluateExpressions(); err != nil {
+		return err
+	}
+
+	col := t.cols[pos]
+	sr, err := convertSerie(col.serie, ctyp, ColumnOptions{TimeFormats: options.TimeFormats})
+	if err != nil {
+		return errors.Wrap(err, ErrCreateSerie.Error())
+	}
+
+	var failed []int
+	for i, v := range col.serie.All() {
+		if v != nil && sr.Get(i) == nil {
+			failed = append(failed, i)
+		}
+	}
+
+	if options.Strict && len(failed) > 0 {
+		rows := make([]string, 0, maxCastErrorRows+1)
+		for i, row := range failed {
+			if i == maxCastErrorRows {
+				rows = append(rows, "...")
+				break
+			}
+			rows = append(rows, fmt.Sprint(row))
+		}
+		err := errors.Errorf("column '%s': %d value(s) can't be converted to '%s' at rows %s", name, len(failed), ctyp, strings.Join(rows, ", "))
+		return errors.Wrap(err, ErrCastColumn.Error())
+	}
+
+	col.typ = ctyp
+	col.serie = sr
+	t.markColumn(col.name)
+	return nil
+}
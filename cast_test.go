@@ -0,0 +1,42 @@
+package datatable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func TestCastColumn(t *testing.T) {
+	tb := datatable.New("test")
+	tb.AddColumn("id", datatable.Float64, datatable.Values(1.0, 2.0, 3.0), datatable.ColumnLabel("Identifier"))
+	tb.AddColumn("date", datatable.String, datatable.Values("23/01/2013", "oops", nil), datatable.ColumnHidden(true))
+	tb.AddColumn("next", datatable.Int, datatable.Expr("`id` + 1"))
+
+	assert.NoError(t, tb.CastColumn("id", datatable.Int))
+	col := tb.Column("id")
+	assert.Equal(t, datatable.Int, col.Type())
+	assert.Equal(t, "Identifier", col.Label())
+	checkTable(t, tb,
+		"id", "next",
+		1, 2,
+		2, 3,
+		3, 4,
+	)
+
+	// strict mode reports the failed rows
+	err := tb.CastColumn("date", datatable.Time, datatable.CastTimeFormats("02/01/2006"), datatable.CastStrict(true))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "at rows 1")
+	assert.Equal(t, datatable.String, tb.Column("date").Type())
+
+	assert.NoError(t, tb.CastColumn("date", datatable.Time, datatable.CastTimeFormats("02/01/2006")))
+	col = tb.Column("date")
+	assert.Equal(t, datatable.Time, col.Type())
+	assert.False(t, col.IsVisible())
+	assert.Equal(t, []interface{}{time.Date(2013, time.January, 23, 0, 0, 0, 0, time.UTC), nil, nil}, col.Serie().All())
+
+	assert.Error(t, tb.CastColumn("unknown", datatable.Int))
+	assert.Error(t, tb.CastColumn("id", datatable.ColumnType("unknown")))
+}
@@ -174,6 +174,40 @@ func ColumnTypes() []ColumnType {
 	return ctyp
 }
 
+// commonColumnType returns the narrowest column type able to hold values of all types.
+// Integers are promoted to int64, numbers to float64, other mixed types to raw.
+func commonColumnType(types ...ColumnType) ColumnType {
+	if len(types) == 0 {
+		return Raw
+	}
+
+	same, integers, numbers := true, true, true
+	for _, typ := range types {
+		if typ != types[0] {
+			same = false
+		}
+		switch typ {
+		case Int, Int32, Int64:
+		case Float32, Float64:
+			integers = false
+		default:
+			integers = false
+			numbers = false
+		}
+	}
+
+	switch {
+	case same:
+		return types[0]
+	case integers:
+		return Int64
+	case numbers:
+		return Float64
+	default:
+		return Raw
+	}
+}
+
 // newColumnSerie to create a serie from a known type
 func newColumnSerie(ctyp ColumnType, options ColumnOptions) (serie.Serie, error) {
 	if s, ok := ctypes[ctyp]; ok {
@@ -183,6 +217,16 @@ func newColumnSerie(ctyp ColumnType, options ColumnOptions) (serie.Serie, error)
 	return nil, errors.Wrap(err, ErrUnknownColumnType.Error())
 }
 
+// convertSerie converts the values of a serie to a new serie of a known type
+func convertSerie(sr serie.Serie, ctyp ColumnType, options ColumnOptions) (serie.Serie, error) {
+	cs, err := newColumnSerie(ctyp, options)
+	if err != nil {
+		return nil, err
+	}
+	cs.Append(sr.All()...)
+	return cs, nil
+}
+
 // Column describes a column in our datatable
 type Column interface {
 	Name() string
@@ -200,14 +244,31 @@ type Column interface {
 }
 
 type column struct {
-	name     string
-	typ      ColumnType
-	hidden   bool
-	label    string
-	attrs    map[string]interface{}
-	formulae string
-	expr     expr.Node
-	serie    serie.Serie
+	name       string
+	typ        ColumnType
+	hidden     bool
+	label      string
+	attrs      map[string]interface{}
+	formulae   string
+	expr       expr.Node
+	deps       []string
+	aggregated bool
+	serie      serie.Serie
+}
+
+// parse parses the formulae and finds the column references
+func (c *column) parse() error {
+	parsed, err := expr.Parse(c.formulae)
+	if err != nil {
+		return err
+	}
+	if err := checkFunctions(parsed); err != nil {
+		return err
+	}
+	c.expr = parsed
+	c.deps = exprRefs(parsed)
+	c.aggregated = exprAggregated(parsed)
+	return nil
 }
 
 func (c *column) Serie() serie.Serie {
@@ -239,14 +300,16 @@ func (c *column) SetLabel(label string) Column {
 
 func (c *column) Clone() Column {
 	return &column{
-		name:     c.name,
-		typ:      c.typ,
-		hidden:   c.hidden,
-		label:    c.label,
-		attrs:    c.attrs,
-		formulae: c.formulae,
-		expr:     c.expr,
-		serie:    c.serie.Copy(),
+		name:       c.name,
+		typ:        c.typ,
+		hidden:     c.hidden,
+		label:      c.label,
+		attrs:      c.attrs,
+		formulae:   c.formulae,
+		expr:       c.expr,
+		deps:       c.deps,
+		aggregated: c.aggregated,
+		serie:      c.serie.Copy(),
 	}
 }
 
@@ -282,9 +345,7 @@ func (c *column) emptyCopy() *column {
 		serie:    c.serie.EmptyCopy(),
 	}
 	if len(cpy.formulae) > 0 {
-		if parsed, err := expr.Parse(cpy.formulae); err == nil {
-			cpy.expr = parsed
-		}
+		cpy.parse()
 	}
 	return cpy
 }
@@ -300,9 +361,19 @@ func (c *column) copy() *column {
 		serie:    c.serie.Copy(),
 	}
 	if len(cpy.formulae) > 0 {
-		if parsed, err := expr.Parse(cpy.formulae); err == nil {
-			cpy.expr = parsed
-		}
+		cpy.parse()
 	}
 	return cpy
 }
+
+// plainCopy copies the structure of the column without values and without expression
+func (c *column) plainCopy() *column {
+	return &column{
+		name:   c.name,
+		typ:    c.typ,
+		label:  c.label,
+		attrs:  c.attrs,
+		hidden: c.hidden,
+		serie:  c.serie.EmptyCopy(),
+	}
+}
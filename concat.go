@@ -1,64 +1,257 @@
 package datatable
 
+import (
+	"github.com/pkg/errors"
+)
+
+// ConcatOptions describes options to be apply on a concat
+type ConcatOptions struct {
+	Promote      bool   // promotes the type of the columns with the same name
+	Strict       bool   // tables must have the same columns with the same types
+	ByPosition   bool   // aligns the columns by position, names of the first table are used
+	SourceColumn string // adds a column with the name of the source table
+}
+
+// ConcatOption sets concat options
+type ConcatOption func(opts *ConcatOptions)
+
+// ConcatPromote promotes the type of the columns with the same name (default false):
+// integers to int64, numbers to float64, other mixed types to string.
+// Without promotion, columns with different types can't be concatenated.
+// Promote can't be used with Strict.
+func ConcatPromote(v bool) ConcatOption {
+	return func(opts *ConcatOptions) {
+		opts.Promote = v
+	}
+}
+
+// ConcatStrict requires the tables to have the same columns with the same types
+// Strict can't be used with Promote.
+func ConcatStrict(v bool) ConcatOption {
+	return func(opts *ConcatOptions) {
+		opts.Strict = v
+	}
+}
+
+// ConcatByPosition aligns the columns by position instead of by name
+func ConcatByPosition(v bool) ConcatOption {
+	return func(opts *ConcatOptions) {
+		opts.ByPosition = v
+	}
+}
+
+// ConcatSourceColumn adds a column {name} with the name of the source table of each row
+func ConcatSourceColumn(name string) ConcatOption {
+	return func(opts *ConcatOptions) {
+		opts.SourceColumn = name
+	}
+}
+
+// newConcatOptions to build the ConcatOptions
+func newConcatOptions(opt ...ConcatOption) ConcatOptions {
+	var opts ConcatOptions
+	for _, o := range opt {
+		o(&opts)
+	}
+	return opts
+}
+
 // Concat datatables
 func (left *DataTable) Concat(table ...*DataTable) (*DataTable, error) {
-	out := left.EmptyCopy()
-	out.dirty = true
-
 	tables := make([]*DataTable, 0, 1+len(table))
 	tables = append(tables, left)
 	tables = append(tables, table...)
+	return concat(tables, newConcatOptions())
+}
 
-	for _, t := range tables {
-		if t == nil {
-			continue
+// Concat datatables
+func Concat(tables []*DataTable, opt ...ConcatOption) (*DataTable, error) {
+	return concat(tables, newConcatOptions(opt...))
+}
+
+func concat(table []*DataTable, options ConcatOptions) (*DataTable, error) {
+	tables := make([]*DataTable, 0, len(table))
+	for _, t := range table {
+		if t != nil {
+			tables = append(tables, t)
+		}
+	}
+	if len(tables) == 0 {
+		return nil, ErrNoTables
+	}
+	if options.Strict && options.Promote {
+		err := errors.New("strict and promote can't be used together")
+		return nil, errors.Wrap(err, ErrConcatOptions.Error())
+	}
+	left := tables[0]
+
+	if options.ByPosition {
+		names := make([]string, 0, len(left.cols))
+		for _, col := range left.cols {
+			names = append(names, col.name)
+		}
+		for i := 1; i < len(tables); i++ {
+			t, err := tables[i].renameByPosition(names)
+			if err != nil {
+				return nil, err
+			}
+			tables[i] = t
+		}
+	}
+
+	if options.Strict {
+		for _, t := range tables[1:] {
+			if err := checkSameColumns(left, t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// promoted types
+	types := make(map[string]ColumnType)
+	if options.Promote {
+		mtypes := make(map[string][]ColumnType)
+		for _, t := range tables {
+			for _, col := range t.cols {
+				mtypes[col.name] = append(mtypes[col.name], col.typ)
+			}
 		}
+		for name, typs := range mtypes {
+			types[name] = concatColumnType(typs...)
+		}
+	}
+
+	out := left.EmptyCopy()
+	out.dirty = true
+	for _, oc := range out.cols {
+		if err := oc.promote(types[oc.name]); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, t := range tables {
 		for _, tc := range t.cols {
 			pos := out.ColumnIndex(tc.name)
-			if pos >= 0 {
-				oc := out.cols[pos]
-				if oc.IsComputed() {
-					oc.serie.Grow(out.nrows - oc.serie.Len() + tc.serie.Len())
-					continue
-				}
-				if err := oc.serie.Concat(tc.serie); err != nil {
+			if pos < 0 {
+				oc := tc.emptyCopy()
+				if err := oc.promote(types[oc.name]); err != nil {
 					return nil, err
 				}
-			} else {
-				out.cols = append(out.cols, tc.emptyCopy())
-				oc := out.cols[len(out.cols)-1]
-				oc.serie.Grow(out.nrows - oc.serie.Len())
-				if oc.IsComputed() {
-					oc.serie.Grow(tc.serie.Len())
-					continue
-				}
-				if err := oc.serie.Concat(tc.serie); err != nil {
+				oc.serie.Grow(out.nrows)
+				out.cols = append(out.cols, oc)
+				pos = len(out.cols) - 1
+			}
+
+			oc := out.cols[pos]
+			if oc.IsComputed() {
+				oc.serie.Grow(out.nrows - oc.serie.Len() + tc.serie.Len())
+				continue
+			}
+
+			src := tc.serie
+			if options.Promote && tc.typ != oc.typ {
+				cs, err := convertSerie(src, oc.typ, ColumnOptions{})
+				if err != nil {
 					return nil, err
 				}
+				src = cs
+			}
+			if err := oc.serie.Concat(src); err != nil {
+				err = errors.Wrapf(err, "column '%s' of table '%s'", tc.name, t.name)
+				return nil, errors.Wrap(err, ErrColumnTypeMismatch.Error())
 			}
 		}
 		out.nrows += t.nrows
+
+		// check
+		for _, oc := range out.cols {
+			size := out.nrows - oc.serie.Len()
+			if size > 0 {
+				oc.serie.Grow(size)
+			}
+		}
 	}
 
-	// check
-	for _, oc := range out.cols {
-		size := out.nrows - oc.serie.Len()
-		if size > 0 {
-			oc.serie.Grow(size)
+	if len(options.SourceColumn) > 0 {
+		sources := make([]interface{}, 0, out.nrows)
+		for _, t := range tables {
+			for i := 0; i < t.nrows; i++ {
+				sources = append(sources, t.name)
+			}
+		}
+		if err := out.AddColumn(options.SourceColumn, String, Values(sources...)); err != nil {
+			err = errors.Wrapf(err, "can't add column '%s'", options.SourceColumn)
+			return nil, errors.Wrap(err, ErrCantAddColumn.Error())
 		}
 	}
 
 	return out, nil
 }
 
-// Concat datatables
-func Concat(tables []*DataTable) (*DataTable, error) {
-	switch len(tables) {
-	case 0:
-		return nil, ErrNoTables
-	case 1:
-		return tables[0].Concat()
-	default:
-		return tables[0].Concat(tables[1:]...)
+// promote converts the column to type ctyp
+func (c *column) promote(ctyp ColumnType) error {
+	if len(ctyp) == 0 || ctyp == c.typ || c.IsComputed() {
+		return nil
+	}
+	cs, err := convertSerie(c.serie, ctyp, ColumnOptions{})
+	if err != nil {
+		return err
+	}
+	c.typ = ctyp
+	c.serie = cs
+	return nil
+}
+
+// concatColumnType returns the promoted type of a column, see ConcatPromote
+func concatColumnType(types ...ColumnType) ColumnType {
+	typ := commonColumnType(types...)
+	if typ != Raw {
+		return typ
+	}
+	for _, t := range types {
+		if t != Raw {
+			return String
+		}
+	}
+	return Raw
+}
+
+// renameByPosition creates a view of the datatable with the columns renamed by position
+// The extra columns keep their name, which can't be one of the names.
+func (t *DataTable) renameByPosition(names []string) (*DataTable, error) {
+	cpy := &DataTable{
+		name:    t.name,
+		nrows:   t.nrows,
+		dirty:   t.isDirty(),
+		hasExpr: t.hasExpr,
+		cols:    make([]*column, len(t.cols)),
+	}
+	positional := make(map[string]bool, len(names))
+	for _, name := range names {
+		positional[name] = true
+	}
+	for i, col := range t.cols {
+		c := *col
+		if i < len(names) {
+			c.name = names[i]
+		} else if positional[c.name] {
+			err := errors.Errorf("extra column '%s' of table '%s' has the name of a positional column", c.name, t.name)
+			return nil, errors.Wrap(err, ErrPositionalNameClash.Error())
+		}
+		cpy.cols[i] = &c
+	}
+	return cpy, nil
+}
+
+// checkSameColumns checks if the tables have the same columns with the same types
+func checkSameColumns(left, right *DataTable) error {
+	for _, t := range [2][2]*DataTable{{left, right}, {right, left}} {
+		for _, col := range t[0].cols {
+			if t[1].Column(col.name) == nil {
+				err := errors.Errorf("column '%s' not found in table '%s'", col.name, t[1].name)
+				return errors.Wrap(err, ErrColumnNotFound.Error())
+			}
+		}
 	}
+	return checkColumnTypes(left, right)
 }
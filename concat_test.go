@@ -111,3 +111,93 @@ func TestConcatWithExpr(t *testing.T) {
 		"Marcel", "Martin", "Paris", time.Date(1976, time.November, 24, 0, 0, 0, 0, time.UTC), int64(39), "PARIS",
 	)
 }
+
+func TestConcatPromote(t *testing.T) {
+	a := datatable.New("a")
+	a.AddColumn("id", datatable.Int, datatable.Values(1, 2))
+	a.AddColumn("score", datatable.Int32, datatable.Values(10, 20))
+
+	b := datatable.New("b")
+	b.AddColumn("id", datatable.Int64, datatable.Values(3))
+	b.AddColumn("score", datatable.Float64, datatable.Values(3.5))
+
+	c := datatable.New("c")
+	c.AddColumn("id", datatable.String, datatable.Values("x4"))
+
+	// no promotion by default
+	_, err := a.Concat(b)
+	assert.Error(t, err)
+	_, err = datatable.Concat([]*datatable.DataTable{a, b})
+	assert.Error(t, err)
+
+	dt, err := datatable.Concat([]*datatable.DataTable{a, b, c}, datatable.ConcatPromote(true))
+	assert.NoError(t, err)
+	assert.Equal(t, datatable.String, dt.Column("id").Type())
+	assert.Equal(t, datatable.Float64, dt.Column("score").Type())
+	checkTable(t, dt,
+		"id", "score",
+		"1", 10.0,
+		"2", 20.0,
+		"3", 3.5,
+		"x4", nil,
+	)
+
+	// promote and strict are conflicting
+	_, err = datatable.Concat([]*datatable.DataTable{a, b}, datatable.ConcatPromote(true), datatable.ConcatStrict(true))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrConcatOptions.Error())
+
+	dt, err = datatable.Concat([]*datatable.DataTable{a, b}, datatable.ConcatPromote(true), datatable.ConcatSourceColumn("source"))
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"id", "score", "source",
+		int64(1), 10.0, "a",
+		int64(2), 20.0, "a",
+		int64(3), 3.5, "b",
+	)
+}
+
+func TestConcatStrict(t *testing.T) {
+	a, b, c := sampleForConcat(t)
+
+	dt, err := datatable.Concat([]*datatable.DataTable{a, b}, datatable.ConcatStrict(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 8, dt.NumRows())
+
+	_, err = datatable.Concat([]*datatable.DataTable{a, c}, datatable.ConcatStrict(true))
+	assert.Error(t, err)
+}
+
+func TestConcatByPosition(t *testing.T) {
+	a := datatable.New("a")
+	a.AddColumn("name", datatable.String, datatable.Values("Léon"))
+	a.AddColumn("city", datatable.String, datatable.Values("Paris"))
+
+	b := datatable.New("b")
+	b.AddColumn("nom", datatable.String, datatable.Values("Marion"))
+	b.AddColumn("ville", datatable.String, datatable.Values("Lyon"))
+	b.AddColumn("age", datatable.Int, datatable.Values(40))
+
+	dt, err := datatable.Concat([]*datatable.DataTable{a, b}, datatable.ConcatByPosition(true))
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"name", "city", "age",
+		"Léon", "Paris", nil,
+		"Marion", "Lyon", 40,
+	)
+
+	_, err = datatable.Concat([]*datatable.DataTable{a, b}, datatable.ConcatByPosition(true), datatable.ConcatStrict(true))
+	assert.Error(t, err)
+
+	// an extra column can't have the name of a positional column
+	c := datatable.New("c")
+	c.AddColumn("nom", datatable.String, datatable.Values("Paul"))
+	c.AddColumn("ville", datatable.String, datatable.Values("Lille"))
+	c.AddColumn("name", datatable.String, datatable.Values("Pierre"))
+	_, err = datatable.Concat([]*datatable.DataTable{a, c}, datatable.ConcatByPosition(true))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrPositionalNameClash.Error())
+
+	_, err = datatable.Concat(nil)
+	assert.Error(t, err)
+}
@@ -4,7 +4,7 @@ package datatable
 func (t *DataTable) EmptyCopy() *DataTable {
 	cpy := &DataTable{
 		name:    t.name,
-		dirty:   t.dirty,
+		dirty:   t.isDirty(),
 		hasExpr: t.hasExpr,
 		nrows:   0,
 		cols:    make([]*column, len(t.cols)),
@@ -31,5 +31,14 @@ func (t *DataTable) Copy() *DataTable {
 		cpy.cols[i] = col.copy()
 	}
 
+	if !t.dirty {
+		for i := range t.dirtyRows {
+			cpy.markRows(i, i+1)
+		}
+		for name := range t.dirtyCols {
+			cpy.markColumn(name)
+		}
+	}
+
 	return cpy
 }
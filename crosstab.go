@@ -0,0 +1,248 @@
+package datatable
+
+import (
+	"fmt"
+
+	"github.com/datasweet/cast"
+	"github.com/pkg/errors"
+)
+
+// Normalization defines how the cells of a crosstab are normalized
+type Normalization uint8
+
+const (
+	NormalizeNone   Normalization = iota
+	NormalizeRow                  // share of the row total
+	NormalizeColumn               // share of the column total
+	NormalizeAll                  // share of the grand total
+)
+
+// CrosstabOptions describes options to be apply on a crosstab
+type CrosstabOptions struct {
+	Values      string // aggregated column, counts the rows if empty
+	Agg         AggregationType
+	Margins     bool
+	MarginsName string
+	Normalize   Normalization
+}
+
+// CrosstabOption sets crosstab options
+type CrosstabOption func(opts *CrosstabOptions)
+
+// CrosstabValues aggregates a column instead of counting the rows
+func CrosstabValues(field string, agg AggregationType) CrosstabOption {
+	return func(opts *CrosstabOptions) {
+		opts.Values = field
+		opts.Agg = agg
+	}
+}
+
+// CrosstabMargins adds a total row and a total column
+func CrosstabMargins(v bool) CrosstabOption {
+	return func(opts *CrosstabOptions) {
+		opts.Margins = v
+	}
+}
+
+// CrosstabMarginsName sets the name of the total row and column (default "Total")
+func CrosstabMarginsName(name string) CrosstabOption {
+	return func(opts *CrosstabOptions) {
+		opts.MarginsName = name
+	}
+}
+
+// CrosstabNormalize divides the cells by the row, column or grand total
+func CrosstabNormalize(v Normalization) CrosstabOption {
+	return func(opts *CrosstabOptions) {
+		opts.Normalize = v
+	}
+}
+
+// Crosstab computes a frequency table of two columns of the datatable.
+// There is one row per value of {rowCol} and one column per value of {colCol}.
+// The cells count the rows, or aggregate a column with CrosstabValues.
+// If margins are added and {rowCol} is not a string column, the row column becomes raw.
+func Crosstab(dt *DataTable, rowCol, colCol string, opt ...CrosstabOption) (*DataTable, error) {
+	if dt == nil {
+		return nil, ErrNilDatatable
+	}
+
+	options := CrosstabOptions{
+		Agg:         Count,
+		MarginsName: "Total",
+	}
+	for _, o := range opt {
+		o(&options)
+	}
+
+	cols := []string{rowCol, colCol}
+	if len(options.Values) > 0 {
+		cols = append(cols, options.Values)
+	}
+	if err := dt.checkColumns(cols...); err != nil {
+		return nil, err
+	}
+
+	// only the rows in a column of the crosstab
+	src, err := dt.WhereE(func(row Row) bool {
+		return row.Get(colCol) != nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// without values, counts the rows with a column of ones
+	if len(options.Values) == 0 {
+		options.Values = "#rows"
+		for k := 1; src.ColumnIndex(options.Values) >= 0; k++ {
+			options.Values = fmt.Sprintf("#rows%d", k)
+		}
+		ones := make([]interface{}, src.nrows)
+		for i := range ones {
+			ones[i] = 1
+		}
+		if err := src.AddColumn(options.Values, Int, Values(ones...)); err != nil {
+			return nil, err
+		}
+	}
+
+	var fill interface{}
+	switch options.Agg {
+	case Count, CountDistinct, Sum:
+		fill = 0
+	}
+
+	pivot, colValues, err := src.pivot([]string{rowCol}, colCol, options.Values, options.Agg, PivotFill(fill))
+	if err != nil {
+		return nil, err
+	}
+	if !options.Margins && options.Normalize == NormalizeNone {
+		return pivot, nil
+	}
+
+	// margins
+	// totals by hash of the key
+	totals := func(name string) (map[uint64]interface{}, error) {
+		groups, err := src.GroupBy(GroupBy{
+			Name: name,
+			Keyer: func(row Row) (interface{}, bool) {
+				return row.Get(name), true
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		out, err := groups.Aggregate(AggregateBy{Type: options.Agg, Field: options.Values})
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[uint64]interface{}, out.nrows)
+		for i := 0; i < out.nrows; i++ {
+			m[hashKeys([]interface{}{out.cols[0].serie.Get(i)})] = out.cols[1].serie.Get(i)
+		}
+		return m, nil
+	}
+	rowTotals, err := totals(rowCol)
+	if err != nil {
+		return nil, err
+	}
+	colTotals, err := totals(colCol)
+	if err != nil {
+		return nil, err
+	}
+	all, err := src.Aggregate(AggregateBy{Type: options.Agg, Field: options.Values})
+	if err != nil {
+		return nil, err
+	}
+	var grand interface{}
+	if all.nrows > 0 {
+		grand = all.cols[0].serie.Get(0)
+	}
+
+	normalize := func(v, rowTotal, colTotal interface{}) interface{} {
+		var denom interface{}
+		switch options.Normalize {
+		case NormalizeNone:
+			return v
+		case NormalizeRow:
+			denom = rowTotal
+		case NormalizeColumn:
+			denom = colTotal
+		case NormalizeAll:
+			denom = grand
+		}
+		n, ok := cast.AsFloat64(v)
+		if !ok {
+			return nil
+		}
+		d, ok := cast.AsFloat64(denom)
+		if !ok || d == 0 {
+			return nil
+		}
+		return n / d
+	}
+
+	// create columns
+	out := New(dt.name)
+	key := pivot.cols[0].plainCopy()
+	if options.Margins && key.typ != String {
+		key.typ = Raw
+		if key.serie, err = newColumnSerie(Raw, ColumnOptions{}); err != nil {
+			return nil, err
+		}
+	}
+	if err := out.addColumn(key); err != nil {
+		err = errors.Wrapf(err, "can't add column '%s'", rowCol)
+		return nil, errors.Wrap(err, ErrCantAddColumn.Error())
+	}
+
+	names := pivot.Columns()[1:]
+	if options.Margins {
+		names = append(names, options.MarginsName)
+	}
+	typ := options.Agg.columnType(src.Column(options.Values).Type())
+	if options.Normalize != NormalizeNone {
+		typ = Float64
+	}
+	for _, name := range names {
+		if err := out.AddColumn(name, typ); err != nil {
+			err = errors.Wrapf(err, "can't add column '%s'", name)
+			return nil, errors.Wrap(err, ErrCantAddColumn.Error())
+		}
+	}
+
+	// fill cells
+	ncols := len(pivot.cols) - 1
+	for i := 0; i < pivot.nrows; i++ {
+		k := pivot.cols[0].serie.Get(i)
+		rowTotal := rowTotals[hashKeys([]interface{}{k})]
+
+		row := make([]interface{}, 0, len(out.cols))
+		row = append(row, k)
+		for j := 1; j <= ncols; j++ {
+			colTotal := colTotals[hashKeys([]interface{}{colValues[j-1]})]
+			row = append(row, normalize(pivot.cols[j].serie.Get(i), rowTotal, colTotal))
+		}
+		if options.Margins {
+			row = append(row, normalize(rowTotal, rowTotal, grand))
+		}
+		if err := out.AppendRow(row...); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.Margins {
+		row := make([]interface{}, 0, len(out.cols))
+		row = append(row, options.MarginsName)
+		for j := 1; j <= ncols; j++ {
+			colTotal := colTotals[hashKeys([]interface{}{colValues[j-1]})]
+			row = append(row, normalize(colTotal, grand, colTotal))
+		}
+		row = append(row, normalize(grand, grand, grand))
+		if err := out.AppendRow(row...); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,94 @@
+package datatable_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func sampleForCrosstab() *datatable.DataTable {
+	dt := datatable.New("quality")
+	dt.AddColumn("line", datatable.String, datatable.Values("A", "A", "A", "B", "B", "B", "B"))
+	dt.AddColumn("status", datatable.String, datatable.Values("ok", "ok", "ko", "ok", "ko", "ko", nil))
+	dt.AddColumn("cost", datatable.Float64, datatable.Values(1, 2, 3, 4, 5, 6, 7))
+	return dt
+}
+
+func TestCrosstab(t *testing.T) {
+	dt := sampleForCrosstab()
+
+	out, err := datatable.Crosstab(dt, "line", "status")
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"line", "ko", "ok",
+		"A", int64(1), int64(2),
+		"B", int64(2), int64(1),
+	)
+
+	out, err = datatable.Crosstab(dt, "line", "status", datatable.CrosstabMargins(true))
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"line", "ko", "ok", "Total",
+		"A", int64(1), int64(2), int64(3),
+		"B", int64(2), int64(1), int64(3),
+		"Total", int64(3), int64(3), int64(6),
+	)
+
+	out, err = datatable.Crosstab(dt, "line", "status",
+		datatable.CrosstabValues("cost", datatable.Sum),
+		datatable.CrosstabMargins(true),
+		datatable.CrosstabNormalize(datatable.NormalizeAll),
+	)
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"line", "ko", "ok", "Total",
+		"A", 3.0/21, 3.0/21, 6.0/21,
+		"B", 11.0/21, 4.0/21, 15.0/21,
+		"Total", 14.0/21, 7.0/21, 1.0,
+	)
+
+	out, err = datatable.Crosstab(dt, "line", "status", datatable.CrosstabNormalize(datatable.NormalizeRow))
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"line", "ko", "ok",
+		"A", 1.0/3, 2.0/3,
+		"B", 2.0/3, 1.0/3,
+	)
+
+	_, err = datatable.Crosstab(dt, "line", "unknown")
+	assert.Error(t, err)
+}
+
+func TestCrosstabNilRows(t *testing.T) {
+	dt := datatable.New("quality")
+	dt.AddColumn("line", datatable.String, datatable.Values("A", nil, nil, "B"))
+	dt.AddColumn("status", datatable.String, datatable.Values("ok", "ok", "ko", "ok"))
+
+	// the rows without line are counted
+	out, err := datatable.Crosstab(dt, "line", "status", datatable.CrosstabMargins(true))
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"line", "ko", "ok", "Total",
+		"A", int64(0), int64(1), int64(1),
+		nil, int64(1), int64(1), int64(2),
+		"B", int64(0), int64(1), int64(1),
+		"Total", int64(1), int64(3), int64(4),
+	)
+}
+
+func TestCrosstabNilNames(t *testing.T) {
+	dt := datatable.New("quality")
+	dt.AddColumn("line", datatable.String, datatable.Values(nil, "<nil>", "<nil>"))
+	dt.AddColumn("status", datatable.String, datatable.Values("ok", "ok", "ko"))
+
+	// the totals of the nil line and the "<nil>" line are not merged
+	out, err := datatable.Crosstab(dt, "line", "status", datatable.CrosstabMargins(true))
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"line", "ko", "ok", "Total",
+		nil, int64(0), int64(1), int64(1),
+		"<nil>", int64(1), int64(1), int64(2),
+		"Total", int64(1), int64(2), int64(3),
+	)
+}
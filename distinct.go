@@ -0,0 +1,137 @@
+package datatable
+
+import (
+	"reflect"
+
+	"github.com/xinzf/datatable/serie"
+)
+
+// DuplicateKeep defines which row is kept when dropping duplicates
+type DuplicateKeep uint8
+
+const (
+	KeepFirst DuplicateKeep = iota // keeps the first occurrence
+	KeepLast                       // keeps the last occurrence
+	KeepNone                       // drops all duplicated rows
+)
+
+// Distinct returns a copy of the datatable without duplicate rows.
+// Rows are compared on {cols}, all visible columns by default.
+// The first occurrence is kept.
+func (t *DataTable) Distinct(cols ...string) (*DataTable, error) {
+	return t.DropDuplicates(cols, KeepFirst)
+}
+
+// DropDuplicates returns a copy of the datatable without duplicate rows.
+// Rows are compared on {subset}, all visible columns by default.
+// The original order of the rows is preserved.
+func (t *DataTable) DropDuplicates(subset []string, keep DuplicateKeep) (*DataTable, error) {
+	if len(subset) == 0 {
+		subset = t.Columns()
+	}
+	if err := t.checkColumns(subset...); err != nil {
+		return nil, err
+	}
+
+	keys, err := t.groupKeys(subset)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int, len(keys))
+	for _, key := range keys {
+		counts[key]++
+	}
+
+	rows := make([]int, 0, len(keys))
+	seen := make(map[int]int, len(counts))
+	for i, key := range keys {
+		seen[key]++
+		switch keep {
+		case KeepFirst:
+			if seen[key] == 1 {
+				rows = append(rows, i)
+			}
+		case KeepLast:
+			if seen[key] == counts[key] {
+				rows = append(rows, i)
+			}
+		case KeepNone:
+			if counts[key] == 1 {
+				rows = append(rows, i)
+			}
+		}
+	}
+
+	return t.pick(rows...), nil
+}
+
+// duplicateKeys returns the index of the first equal row of each row
+// The rows with the same hash are compared with {equal}, a hash collision is not a duplicate.
+func duplicateKeys(hashes []uint64, equal func(i, j int) bool) []int {
+	keys := make([]int, len(hashes))
+	firsts := make(map[uint64][]int, len(hashes))
+	for i, hash := range hashes {
+		keys[i] = i
+		for _, first := range firsts[hash] {
+			if equal(i, first) {
+				keys[i] = first
+				break
+			}
+		}
+		if keys[i] == i {
+			firsts[hash] = append(firsts[hash], i)
+		}
+	}
+	return keys
+}
+
+// groupKeys returns the index of the first equal row of each row, rows are compared on {by}
+func (t *DataTable) groupKeys(by []string) ([]int, error) {
+	hashes, err := t.hashRows(by)
+	if err != nil {
+		return nil, err
+	}
+	series := t.columnSeries(by)
+	return duplicateKeys(hashes, rowsEqual(series, series)), nil
+}
+
+// rowsEqual returns a func checking if the row i of {left} equals the row j of {right}
+func rowsEqual(left, right []serie.Serie) func(i, j int) bool {
+	return func(i, j int) bool {
+		for k, sr := range left {
+			if !reflect.DeepEqual(sr.Get(i), right[k].Get(j)) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// columnSeries returns the series of the columns
+func (t *DataTable) columnSeries(cols []string) []serie.Serie {
+	series := make([]serie.Serie, 0, len(cols))
+	for _, name := range cols {
+		series = append(series, t.cols[t.ColumnIndex(name)].serie)
+	}
+	return series
+}
+
+// hashRows computes the hash code of each row on columns
+func (t *DataTable) hashRows(cols []string) ([]uint64, error) {
+	if err := t.evaluateExpressions(); err != nil {
+		return nil, err
+	}
+
+	series := t.columnSeries(cols)
+
+	hashes := make([]uint64, 0, t.nrows)
+	row := make(Row, len(cols))
+	for i := 0; i < t.nrows; i++ {
+		for j, name := range cols {
+			row[name] = series[j].Get(i)
+		}
+		hashes = append(hashes, hasher.Row(row, cols))
+	}
+	return hashes, nil
+}
@@ -0,0 +1,26 @@
+package datatable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicateKeysCollision(t *testing.T) {
+	// all rows have the same hash
+	values := []string{"a", "b", "a", "c", "b"}
+	keys := duplicateKeys(make([]uint64, len(values)), func(i, j int) bool {
+		return values[i] == values[j]
+	})
+	assert.Equal(t, []int{0, 1, 0, 3, 1}, keys)
+}
+
+func TestMatchRowsCollision(t *testing.T) {
+	// all rows have the same hash
+	values := []string{"a", "b", "c"}
+	others := []string{"c", "a"}
+	matches := matchRows(make([]uint64, len(values)), make([]uint64, len(others)), func(i, j int) bool {
+		return values[i] == others[j]
+	})
+	assert.Equal(t, []int{1, -1, 0}, matches)
+}
@@ -0,0 +1,74 @@
+package datatable_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func sampleForDistinct() *datatable.DataTable {
+	dt := datatable.New("orders")
+	dt.AddColumn("customer", datatable.String, datatable.Values("alice", "bob", "alice", "carol", "bob", nil))
+	dt.AddColumn("product", datatable.String, datatable.Values("tea", "coffee", "tea", "tea", "tea", "tea"))
+	dt.AddColumn("qty", datatable.Int, datatable.Values(1, 2, 1, 3, 4, 5))
+	dt.AddColumn("double", datatable.Int, datatable.Expr("`qty` * 2"))
+	return dt
+}
+
+func TestDistinct(t *testing.T) {
+	dt := sampleForDistinct()
+
+	out, err := dt.Distinct()
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"customer", "product", "qty", "double",
+		"alice", "tea", 1, 2,
+		"bob", "coffee", 2, 4,
+		"carol", "tea", 3, 6,
+		"bob", "tea", 4, 8,
+		nil, "tea", 5, 10,
+	)
+	assert.True(t, out.Column("double").IsComputed())
+
+	out, err = dt.Distinct("product")
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"customer", "product", "qty", "double",
+		"alice", "tea", 1, 2,
+		"bob", "coffee", 2, 4,
+	)
+
+	_, err = dt.Distinct("unknown")
+	assert.Error(t, err)
+}
+
+func TestDropDuplicates(t *testing.T) {
+	dt := sampleForDistinct()
+
+	out, err := dt.DropDuplicates([]string{"customer"}, datatable.KeepLast)
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"customer", "product", "qty", "double",
+		"alice", "tea", 1, 2,
+		"carol", "tea", 3, 6,
+		"bob", "tea", 4, 8,
+		nil, "tea", 5, 10,
+	)
+
+	out, err = dt.DropDuplicates([]string{"customer"}, datatable.KeepNone)
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"customer", "product", "qty", "double",
+		"carol", "tea", 3, 6,
+		nil, "tea", 5, 10,
+	)
+}
+
+func TestRowHash(t *testing.T) {
+	a := datatable.Row{"a": 1, "b": "x", "c": nil}
+	b := datatable.Row{"c": nil, "b": "x", "a": 1}
+	c := datatable.Row{"a": 1, "b": nil, "c": "x"}
+	assert.Equal(t, a.Hash(), b.Hash())
+	assert.NotEqual(t, a.Hash(), c.Hash())
+}
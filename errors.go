@@ -24,6 +24,16 @@ var (
 	ErrCantAddColumn  = errors.New("can't add column")
 )
 
+// Errors in apply.go
+var (
+	ErrApplyFunction = errors.New("apply function")
+)
+
+// Errors in cast.go
+var (
+	ErrCastColumn = errors.New("cast column")
+)
+
 // Errors in column.go
 var (
 	ErrEmptyName         = errors.New("empty name")
@@ -34,12 +44,51 @@ var (
 
 // Errors in concat.go
 var (
-	ErrNoTables = errors.New("no tables")
+	ErrNoTables            = errors.New("no tables")
+	ErrConcatOptions       = errors.New("conflicting concat options")
+	ErrPositionalNameClash = errors.New("positional name clash")
+)
+
+// Errors in select.go
+var (
+	ErrInvalidPattern = errors.New("invalid pattern")
+)
+
+// Errors in setop.go
+var (
+	ErrColumnTypeMismatch = errors.New("column type mismatch")
 )
 
 // Errors in eval_expr
 var (
 	ErrEvaluateExprSizeMismatch = errors.New("size mismatch")
+	ErrEvaluateExpr             = errors.New("evaluate expr")
+	ErrExprCycle                = errors.New("expr cycle")
+	ErrUnknownColumnRef         = errors.New("unknown column reference")
+)
+
+// Errors in fill.go
+var (
+	ErrComputedColumn = errors.New("computed column")
+	ErrFillValue      = errors.New("fill value")
+)
+
+// Errors in function.go
+var (
+	ErrNilFunction           = errors.New("nil function")
+	ErrInvalidFunction       = errors.New("invalid function")
+	ErrFunctionAlreadyExists = errors.New("function already exists")
+	ErrUnknownFunction       = errors.New("unknown function")
+	ErrFunctionCall          = errors.New("function call")
+	ErrFunctionColumnClash   = errors.New("function and column with the same name")
+)
+
+// Errors in query.go
+var (
+	ErrQuerySyntax        = errors.New("query syntax")
+	ErrUnsupportedQuery   = errors.New("unsupported query")
+	ErrUnknownTable       = errors.New("unknown table")
+	ErrAmbiguousColumnRef = errors.New("ambiguous column reference")
 )
 
 // Errors in join.go
@@ -68,4 +117,6 @@ var (
 var (
 	ErrLengthMismatch = errors.New("length mismatch")
 	ErrUpdateRow      = errors.New("update row")
+	ErrOutOfRange     = errors.New("out of range")
+	ErrInsertRow      = errors.New("insert row")
 )
@@ -1,72 +1,270 @@
 package datatable
 
-import "github.com/pkg/errors"
+import (
+	"sort"
+
+	"github.com/datasweet/expr"
+	"github.com/pkg/errors"
+)
+
+// Evaluate computes all columns with a binded expression
+func (t *DataTable) Evaluate() error {
+	if t == nil {
+		return ErrNilDatatable
+	}
+	return t.evaluateExpressions()
+}
+
+// markRows marks the rows [from, to) as changed.
+// Only these rows will be evaluated, except for the aggregated expressions.
+// Nothing is marked without expression.
+func (t *DataTable) markRows(from, to int) {
+	if t.dirty || !t.hasExpr || from >= to {
+		return
+	}
+	if t.dirtyRows == nil {
+		t.dirtyRows = make(map[int]bool, to-from)
+	}
+	for i := from; i < to; i++ {
+		t.dirtyRows[i] = true
+	}
+}
+
+// markColumn marks a column as changed.
+// The column (if computed) and its dependents will be entirely evaluated.
+// Nothing is marked without expression.
+func (t *DataTable) markColumn(name string) {
+	if t.dirty || !t.hasExpr {
+		return
+	}
+	if t.dirtyCols == nil {
+		t.dirtyCols = make(map[string]bool)
+	}
+	t.dirtyCols[name] = true
+}
+
+// isDirty returns true if some expressions must be evaluated
+func (t *DataTable) isDirty() bool {
+	return t.dirty || len(t.dirtyRows) > 0 || len(t.dirtyCols) > 0
+}
+
+// clean marks the datatable as evaluated
+func (t *DataTable) clean() {
+	t.dirty = false
+	t.dirtyRows = nil
+	t.dirtyCols = nil
+}
+
+// exprParams creates the params of the expressions with the values at rows
+// and the registered functions. A column hides a function with the same name, see checkFunctionColumns.
+// If rows is nil, all values are taken
+// The errors of the function calls are recorded in calls.
+func (t *DataTable) exprParams(rows []int, calls *exprCalls) map[string]interface{} {
+	functionsMu.RLock()
+	params := make(map[string]interface{}, len(t.cols)+len(functions))
+	for name, fn := range functions {
+		params[name] = fn.bind(calls)
+	}
+	functionsMu.RUnlock()
+	for _, col := range t.cols {
+		params[col.name] = exprValues(col, rows)
+	}
+	return params
+}
+
+// exprValues returns the values of the column at rows
+// If rows is nil, all values are returned
+func exprValues(col *column, rows []int) []interface{} {
+	if rows == nil {
+		return col.serie.All()
+	}
+	values := make([]interface{}, 0, len(rows))
+	for _, i := range rows {
+		values = append(values, col.serie.Get(i))
+	}
+	return values
+}
 
 // evaluateExpressions to evaluate all columns with a binded expression
+// When only some rows or columns have changed since the last evaluation,
+// only the changed rows and the dependent expressions are evaluated.
 func (t *DataTable) evaluateExpressions() error {
-	if !t.dirty || !t.hasExpr {
+	if !t.hasExpr {
+		t.clean()
+		return nil
+	}
+	if !t.isDirty() {
 		return nil
 	}
 
-	var cols []int
-	var exprCols []int
-	for i, c := range t.cols {
-		if c.IsComputed() {
-			exprCols = append(exprCols, i)
-		} else {
-			cols = append(cols, i)
-		}
+	// computed columns sorted by dependencies
+	exprCols, err := t.exprOrder()
+	if err != nil {
+		return err
 	}
 
-	l := len(exprCols)
-	if l == 0 {
-		t.dirty = false
+	if len(exprCols) == 0 {
+		t.clean()
 		return nil
 	}
 
-	// Initialize params
-	params := make(map[string][]interface{}, len(t.cols))
-	for _, pos := range cols {
-		col := t.cols[pos]
-		params[col.name] = col.serie.All()
+	// changed rows
+	var rows []int
+	if !t.dirty {
+		rows = make([]int, 0, len(t.dirtyRows))
+		for i := range t.dirtyRows {
+			if i < t.nrows {
+				rows = append(rows, i)
+			}
+		}
+		sort.Ints(rows)
 	}
 
-	// Evaluate
+	// columns to be entirely evaluated
+	entire := make(map[string]bool, len(exprCols))
 	for _, idx := range exprCols {
 		col := t.cols[idx]
-		res, err := col.expr.Eval(params)
-		if err != nil {
-			return err
+		if t.dirty || t.dirtyCols[col.name] || (col.aggregated && len(rows) > 0) {
+			entire[col.name] = true
+			continue
+		}
+		for _, dep := range col.deps {
+			if entire[dep] || t.dirtyCols[dep] {
+				entire[col.name] = true
+				break
+			}
 		}
+	}
 
+	// Evaluate
+	var params, partial map[string]interface{}
+	calls := &exprCalls{}
+	for _, idx := range exprCols {
+		col := t.cols[idx]
 		name := col.Name()
 
-		if arr, ok := res.([]interface{}); ok {
-			// Is array
-			ls := col.serie.Len()
-			la := len(arr)
-
-			if t.nrows != ls || la != ls {
-				err := errors.Errorf("evaluate expr : size mismatch %d vs %d", la, ls)
-				return errors.Wrap(err, ErrEvaluateExprSizeMismatch.Error())
+		var at []int
+		var env map[string]interface{}
+		switch {
+		case entire[name]:
+			if params == nil {
+				params = t.exprParams(nil, calls)
 			}
-
-			for i := 0; i < t.nrows; i++ {
-				col.serie.Set(i, arr[i])
+			env = params
+		case len(rows) > 0:
+			if partial == nil {
+				partial = t.exprParams(rows, calls)
 			}
+			env, at = partial, rows
+		default:
+			continue
+		}
 
-		} else {
-			// Is scalar
-			for i := 0; i < t.nrows; i++ {
-				col.serie.Set(i, res)
-			}
+		if err := t.evaluateColumn(col, env, at, calls); err != nil {
+			return err
 		}
 
 		// update dependency
-		params[name] = col.serie.All()
+		if params != nil {
+			params[name] = exprValues(col, nil)
+		}
+		if partial != nil {
+			partial[name] = exprValues(col, rows)
+		}
 	}
 
-	t.dirty = false
+	t.clean()
+
+	return nil
+}
+
+// evaluateColumn evaluates the expression of a column with params
+// and sets the result at rows. If rows is nil, the result is set on all rows.
+func (t *DataTable) evaluateColumn(col *column, params map[string]interface{}, rows []int, calls *exprCalls) error {
+	name := col.Name()
+
+	res, err := expr.Run(col.expr, params)
+	if err = calls.check(err); err != nil {
+		err = errors.Wrapf(err, "column '%s' with expr '%s'", name, col.formulae)
+		return errors.Wrap(err, ErrEvaluateExpr.Error())
+	}
+
+	size := t.nrows
+	if rows != nil {
+		size = len(rows)
+	}
+	at := func(i int) int {
+		if rows != nil {
+			return rows[i]
+		}
+		return i
+	}
+
+	if arr, ok := res.([]interface{}); ok {
+		// Is array
+		ls := col.serie.Len()
+		la := len(arr)
+
+		if t.nrows != ls || la != size {
+			err := errors.Errorf("evaluate expr of column '%s': size mismatch %d vs %d", name, la, size)
+			return errors.Wrap(err, ErrEvaluateExprSizeMismatch.Error())
+		}
+
+		for i := 0; i < size; i++ {
+			col.serie.Set(at(i), arr[i])
+		}
+
+	} else {
+		// Is scalar
+		for i := 0; i < size; i++ {
+			col.serie.Set(at(i), res)
+		}
+	}
 
 	return nil
 }
+
+// evalNode evaluates an expression on all rows and returns the value of each row
+// funcs are added to the registered functions.
+func (t *DataTable) evalNode(node expr.Node, funcs map[string]*function) ([]interface{}, error) {
+	return t.evalNodeAt(node, nil, funcs)
+}
+
+// evalNodeAt evaluates an expression on the rows and returns the value of each row, in order
+// The expression only sees these rows, ie an aggregation is computed on them.
+// If rows is nil, all rows are taken
+func (t *DataTable) evalNodeAt(node expr.Node, rows []int, funcs map[string]*function) ([]interface{}, error) {
+	if err := t.evaluateExpressions(); err != nil {
+		return nil, err
+	}
+
+	size := t.nrows
+	if rows != nil {
+		size = len(rows)
+	}
+
+	calls := &exprCalls{}
+	params := t.exprParams(rows, calls)
+	for name, fn := range funcs {
+		params[name] = fn.bind(calls)
+	}
+
+	res, err := expr.Run(node, params)
+	if err = calls.check(err); err != nil {
+		return nil, errors.Wrap(err, ErrEvaluateExpr.Error())
+	}
+
+	arr, ok := res.([]interface{})
+	if !ok {
+		// scalar
+		arr = make([]interface{}, size)
+		for i := range arr {
+			arr[i] = res
+		}
+	}
+	if len(arr) != size {
+		err := errors.Errorf("size mismatch %d vs %d", len(arr), size)
+		return nil, errors.Wrap(err, ErrEvaluateExprSizeMismatch.Error())
+	}
+	return arr, nil
+}
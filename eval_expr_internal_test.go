@@ -0,0 +1,27 @@
+package datatable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkRowsWithoutExpr(t *testing.T) {
+	tb := New("test")
+	assert.NoError(t, tb.AddColumn("win", Int))
+	for i := 0; i < 1000; i++ {
+		assert.NoError(t, tb.AppendRow(i))
+	}
+	assert.NoError(t, tb.Column("win").Serie().Set(0, 10))
+	tb.markColumn("win")
+	assert.Empty(t, tb.dirtyRows)
+	assert.Empty(t, tb.dirtyCols)
+
+	// the rows are marked once an expression is added
+	assert.NoError(t, tb.AddColumn("double", Int, Expr("`win` * 2")))
+	assert.NoError(t, tb.Evaluate())
+	assert.NoError(t, tb.AppendRow(1, nil))
+	assert.Equal(t, map[int]bool{1000: true}, tb.dirtyRows)
+	assert.NoError(t, tb.Evaluate())
+	assert.Empty(t, tb.dirtyRows)
+}
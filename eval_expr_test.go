@@ -0,0 +1,113 @@
+package datatable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncrementalEvaluate(t *testing.T) {
+	tb := New("test")
+	assert.NoError(t, tb.AddColumn("x", Int, Values(1, 2, 3)))
+	assert.NoError(t, tb.AddColumn("y", Int, Expr("`x` * 2")))
+	assert.NoError(t, tb.AddColumn("z", Int, Expr("`y` + 1")))
+	assert.NoError(t, tb.AddColumn("total", Int, Expr("sum(`x`)")))
+	assert.NoError(t, tb.Evaluate())
+	assert.False(t, tb.isDirty())
+
+	y := tb.cols[tb.ColumnIndex("y")].serie
+	z := tb.cols[tb.ColumnIndex("z")].serie
+	total := tb.cols[tb.ColumnIndex("total")].serie
+	assert.Equal(t, []interface{}{2, 4, 6}, y.All())
+	assert.Equal(t, []interface{}{3, 5, 7}, z.All())
+	assert.Equal(t, []interface{}{6, 6, 6}, total.All())
+
+	// the evaluated rows are not computed again
+	y.Set(0, 100)
+
+	tb.AppendRow(4, nil, nil, nil)
+	assert.Equal(t, map[int]bool{3: true}, tb.dirtyRows)
+	assert.NoError(t, tb.Evaluate())
+	assert.Equal(t, []interface{}{100, 4, 6, 8}, y.All())
+	assert.Equal(t, []interface{}{3, 5, 7, 9}, z.All())
+	assert.Equal(t, []interface{}{10, 10, 10, 10}, total.All())
+
+	assert.NoError(t, tb.Update(1, Row{"x": 5}))
+	assert.Equal(t, map[int]bool{1: true}, tb.dirtyRows)
+	assert.NoError(t, tb.Evaluate())
+	assert.Equal(t, []interface{}{100, 10, 6, 8}, y.All())
+	assert.Equal(t, []interface{}{3, 11, 7, 9}, z.All())
+	assert.Equal(t, []interface{}{13, 13, 13, 13}, total.All())
+
+	// a new computed column is entirely evaluated
+	assert.NoError(t, tb.AddColumn("w", Int, Expr("`y` - `x`")))
+	assert.Nil(t, tb.dirtyRows)
+	assert.Equal(t, map[string]bool{"w": true}, tb.dirtyCols)
+	assert.NoError(t, tb.Evaluate())
+	assert.Equal(t, []interface{}{99, 5, 3, 4}, tb.cols[tb.ColumnIndex("w")].serie.All())
+	assert.Equal(t, []interface{}{100, 10, 6, 8}, y.All())
+
+	// a full recompute
+	assert.NoError(t, tb.DeleteRow(3))
+	assert.True(t, tb.dirty)
+	assert.NoError(t, tb.Evaluate())
+	assert.Equal(t, []interface{}{2, 10, 6}, y.All())
+	assert.Equal(t, []interface{}{3, 11, 7}, z.All())
+	assert.Equal(t, []interface{}{9, 9, 9}, total.All())
+}
+
+func TestIncrementalEvaluateDependency(t *testing.T) {
+	tb := New("test")
+	// the dependency must exist when the column is added
+	err := tb.AddColumn("y", Int, Expr("`x` * 2"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ErrUnknownColumnRef.Error())
+	assert.Equal(t, 0, tb.NumCols())
+
+	assert.NoError(t, tb.AddColumn("x", Int, Values(1, 2, 3)))
+	assert.NoError(t, tb.AddColumn("y", Int, Expr("`x` * 2")))
+	assert.NoError(t, tb.Evaluate())
+	assert.Equal(t, []interface{}{2, 4, 6}, tb.cols[tb.ColumnIndex("y")].serie.All())
+
+	cpy := tb.Copy()
+	assert.NoError(t, cpy.Update(0, Row{"x": 10}))
+	assert.NoError(t, cpy.Evaluate())
+	assert.Equal(t, []interface{}{20, 4, 6}, cpy.cols[cpy.ColumnIndex("y")].serie.All())
+	assert.Equal(t, []interface{}{2, 4, 6}, tb.cols[tb.ColumnIndex("y")].serie.All())
+}
+
+func TestIncrementalEvaluateMovedRows(t *testing.T) {
+	sample := func() *DataTable {
+		tb := New("test")
+		assert.NoError(t, tb.AddColumn("x", Int, Values(5, 1, 3)))
+		assert.NoError(t, tb.AddColumn("y", Int, Expr("`x` * 2")))
+		assert.NoError(t, tb.Evaluate())
+		return tb
+	}
+
+	// sort
+	tb := sample()
+	assert.NoError(t, tb.AppendRow(0, nil))
+	sorted := tb.Sort(SortBy{Column: "x"})
+	assert.Equal(t, Row{"x": 0, "y": 0}, sorted.Row(0))
+	assert.Equal(t, []interface{}{0, 2, 6, 10}, sorted.cols[1].serie.All())
+
+	// swap
+	tb = sample()
+	assert.NoError(t, tb.AppendRow(7, nil))
+	assert.NoError(t, tb.AppendRow(9, nil))
+	tb.SwapRow(0, 3)
+	assert.Equal(t, map[int]bool{0: true, 4: true}, tb.dirtyRows)
+	assert.Equal(t, Row{"x": 7, "y": 14}, tb.Row(0))
+	assert.Equal(t, []interface{}{14, 2, 6, 10, 18}, tb.cols[1].serie.All())
+
+	// copy
+	tb = sample()
+	assert.NoError(t, tb.AppendRow(7, nil))
+	cpy := tb.Copy()
+	cpy.SwapRow(0, 3)
+	assert.NoError(t, cpy.Evaluate())
+	assert.NoError(t, tb.Evaluate())
+	assert.Equal(t, []interface{}{14, 2, 6, 10}, cpy.cols[1].serie.All())
+	assert.Equal(t, []interface{}{10, 2, 6, 14}, tb.cols[1].serie.All())
+}
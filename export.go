@@ -76,14 +76,25 @@ func newExportOptions(opt ...ExportOption) ExportOptions {
 //	}
 //	return rows
 //}
+// ToMap to export the datatable to a json-like struct
+// <!> ToMap panics if an expression can't be evaluated, see ToMapE
 func (t *DataTable) ToMap(opt ...ExportOption) []map[string]interface{} {
+	rows, err := t.ToMapE(opt...)
+	if err != nil {
+		panic(err)
+	}
+	return rows
+}
+
+// ToMapE to export the datatable to a json-like struct
+func (t *DataTable) ToMapE(opt ...ExportOption) ([]map[string]interface{}, error) {
 	if t == nil {
-		return nil
+		return nil, nil
 	}
 
 	opts := newExportOptions(opt...)
 	if err := t.evaluateExpressions(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	type colsDesc struct {
@@ -119,18 +130,28 @@ func (t *DataTable) ToMap(opt ...ExportOption) []map[string]interface{} {
 		}
 		rows = append(rows, r)
 	}
-	return rows
+	return rows, nil
 }
 
 // ToTable to export the datatable to a csv-like struct
+// <!> ToTable panics if an expression can't be evaluated, see ToTableE
 func (t *DataTable) ToTable(opt ...ExportOption) [][]interface{} {
+	rows, err := t.ToTableE(opt...)
+	if err != nil {
+		panic(err)
+	}
+	return rows
+}
+
+// ToTableE to export the datatable to a csv-like struct
+func (t *DataTable) ToTableE(opt ...ExportOption) ([][]interface{}, error) {
 	if t == nil {
-		return nil
+		return nil, nil
 	}
 
 	opts := newExportOptions(opt...)
 	if err := t.evaluateExpressions(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	rows := make([][]interface{}, 0, t.nrows+1)
@@ -153,7 +174,7 @@ func (t *DataTable) ToTable(opt ...ExportOption) [][]interface{} {
 		}
 		rows = append(rows, r)
 	}
-	return rows
+	return rows, nil
 }
 
 // Schema describes a datatable
@@ -169,14 +190,24 @@ type SchemaColumn struct {
 }
 
 // ToSchema to export the datatable to a schema struct
+// <!> ToSchema panics if an expression can't be evaluated, see ToSchemaE
 func (t *DataTable) ToSchema(opt ...ExportOption) *Schema {
+	schema, err := t.ToSchemaE(opt...)
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// ToSchemaE to export the datatable to a schema struct
+func (t *DataTable) ToSchemaE(opt ...ExportOption) (*Schema, error) {
 	if t == nil {
-		return nil
+		return nil, nil
 	}
 
 	opts := newExportOptions(opt...)
 	if err := t.evaluateExpressions(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	schema := &Schema{
@@ -201,5 +232,5 @@ func (t *DataTable) ToSchema(opt ...ExportOption) *Schema {
 		schema.Rows = append(schema.Rows, r)
 	}
 
-	return schema
+	return schema, nil
 }
@@ -150,3 +150,55 @@ func TestToSchema(t *testing.T) {
 	assert.Equal(t, []interface{}{3, "Marine", "Prevost", "Marine PREVOST", "m.prevost@example.com", "Lille"}, schema2.Rows[2])
 	assert.Equal(t, []interface{}{4, "Luc", "Rolland", "Luc ROLLAND", "lucrolland@example.com", "Marseille"}, schema2.Rows[3])
 }
+
+func TestExportWithExprError(t *testing.T) {
+	tb := datatable.New("test")
+	assert.NoError(t, tb.AddColumn("win", datatable.Int, datatable.Values(10, 20)))
+	assert.NoError(t, tb.AddColumn("unknown", datatable.Int, datatable.Values(1, 2)))
+	assert.NoError(t, tb.AddColumn("ratio", datatable.Float64, datatable.Expr("`win` / `unknown`")))
+	// the referenced column is removed
+	tb.RemoveColumn("unknown")
+
+	err := tb.Evaluate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ratio")
+	assert.Contains(t, err.Error(), "`win` / `unknown`")
+
+	_, err = tb.RecordsE()
+	assert.Error(t, err)
+	_, err = tb.RowsE()
+	assert.Error(t, err)
+	_, err = tb.RowE(0)
+	assert.Error(t, err)
+	_, err = tb.ToMapE()
+	assert.Error(t, err)
+	_, err = tb.ToTableE()
+	assert.Error(t, err)
+	_, err = tb.ToSchemaE()
+	assert.Error(t, err)
+	_, err = tb.WhereE(func(row datatable.Row) bool { return true })
+	assert.Error(t, err)
+
+	assert.Panics(t, func() { tb.Rows() })
+	assert.NotPanics(t, func() { assert.Equal(t, datatable.Row{}, tb.Row(0)) })
+
+	_, err = tb.GroupBy(datatable.GroupBy{
+		Name:  "win",
+		Keyer: func(row datatable.Row) (interface{}, bool) { return row.Get("win"), true },
+	})
+	assert.Error(t, err)
+}
+
+func TestRowOutOfRange(t *testing.T) {
+	tb := datatable.New("test")
+	assert.NoError(t, tb.AddColumn("win", datatable.Int, datatable.Values(10, 20)))
+
+	_, err := tb.RowE(2)
+	assert.Error(t, err)
+	assert.Equal(t, datatable.Row{}, tb.Row(2))
+	assert.Equal(t, datatable.Row{}, tb.Row(-1))
+	assert.Nil(t, tb.Row(2).Get("win"))
+	r, err := tb.RowE(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, r.Get("win"))
+}
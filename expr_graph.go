@@ -0,0 +1,168 @@
+package datatable
+
+import (
+	"strings"
+
+	"github.com/datasweet/expr"
+	"github.com/pkg/errors"
+)
+
+// exprRefs returns the column names referenced in an expression
+func exprRefs(node expr.Node) []string {
+	var refs []string
+	seen := make(map[string]bool)
+	walkExpr(node, func(n exprNode) {
+		if n.kind() != exprNameNode {
+			return
+		}
+		name := n.name()
+		if !seen[name] {
+			seen[name] = true
+			refs = append(refs, name)
+		}
+	})
+	return refs
+}
+
+// aggregateBuiltins are the builtins computed over the whole column
+var aggregateBuiltins = map[string]bool{
+	"avg":            true,
+	"count":          true,
+	"count_distinct": true,
+	"cusum":          true,
+	"max":            true,
+	"median":         true,
+	"min":            true,
+	"percentile":     true,
+	"stddev":         true,
+	"sum":            true,
+	"variance":       true,
+}
+
+// exprFunctions returns the registered functions called by the expression
+func exprFunctions(node expr.Node) []string {
+	var names []string
+	seen := make(map[string]bool)
+	walkExpr(node, func(n exprNode) {
+		if n.kind() != exprFunctionNode {
+			return
+		}
+		name := n.name()
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	})
+	return names
+}
+
+// exprAggregated returns true if the value of a row depends on the other rows,
+// ie the expression calls an aggregate builtin
+func exprAggregated(node expr.Node) bool {
+	var aggregated bool
+	walkExpr(node, func(n exprNode) {
+		if n.kind() == exprBuiltinNode && aggregateBuiltins[n.name()] {
+			aggregated = true
+		}
+	})
+	return aggregated
+}
+
+// Dependencies returns the columns referenced by the expression of a column
+func (t *DataTable) Dependencies(name string) ([]string, error) {
+	pos := t.ColumnIndex(name)
+	if pos < 0 {
+		err := errors.Errorf("column '%s' not found", name)
+		return nil, errors.Wrap(err, ErrColumnNotFound.Error())
+	}
+	deps := make([]string, len(t.cols[pos].deps))
+	copy(deps, t.cols[pos].deps)
+	return deps, nil
+}
+
+// checkExprRefs checks if the columns referenced by the expression of a new column exist
+func (t *DataTable) checkExprRefs(col *column) error {
+	for _, dep := range col.deps {
+		if dep != col.name && t.ColumnIndex(dep) < 0 {
+			err := errors.Errorf("column '%s' references unknown column '%s'", col.name, dep)
+			return errors.Wrap(err, ErrUnknownColumnRef.Error())
+		}
+	}
+	return nil
+}
+
+// checkExprCycle checks if the expression of a new column creates a cycle
+func (t *DataTable) checkExprCycle(col *column) error {
+	visited := make(map[string]bool)
+	var visit func(path []string, deps []string) error
+	visit = func(path []string, deps []string) error {
+		for _, dep := range deps {
+			cycle := append(path, dep)
+			if dep == col.name {
+				err := errors.Errorf("column '%s' has a cycle: %s", col.name, strings.Join(cycle, " -> "))
+				return errors.Wrap(err, ErrExprCycle.Error())
+			}
+			if visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			if pos := t.ColumnIndex(dep); pos >= 0 {
+				if err := visit(cycle, t.cols[pos].deps); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return visit([]string{col.name}, col.deps)
+}
+
+// exprOrder returns the index of the computed columns sorted by dependencies
+func (t *DataTable) exprOrder() ([]int, error) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := make([]int, len(t.cols))
+	order := make([]int, 0, len(t.cols))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			err := errors.Errorf("column '%s' is in a cycle", t.cols[i].name)
+			return errors.Wrap(err, ErrExprCycle.Error())
+		}
+
+		state[i] = visiting
+		col := t.cols[i]
+		for _, dep := range col.deps {
+			j := t.ColumnIndex(dep)
+			if j < 0 {
+				err := errors.Errorf("column '%s' with expr '%s' references unknown column '%s'", col.name, col.formulae, dep)
+				return errors.Wrap(err, ErrUnknownColumnRef.Error())
+			}
+			if t.cols[j].IsComputed() {
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+		}
+		state[i] = done
+		order = append(order, i)
+		return nil
+	}
+
+	for i, col := range t.cols {
+		if col.IsComputed() {
+			if err := visit(i); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return order, nil
+}
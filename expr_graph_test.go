@@ -0,0 +1,72 @@
+package datatable_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func TestExprDependencies(t *testing.T) {
+	tb := datatable.New("test")
+	assert.NoError(t, tb.AddColumn("win", datatable.Int, datatable.Values(10, 20, 666)))
+	assert.NoError(t, tb.AddColumn("loose", datatable.Int, datatable.Values(6, 5, 666)))
+	assert.NoError(t, tb.AddColumn("winRate", datatable.Float64, datatable.Expr("`win` * 100 / (`win` + `loose`)")))
+	assert.NoError(t, tb.AddColumn("winRate %", datatable.String, datatable.Expr("`winRate` ~ \" %\"")))
+
+	// the expressions are evaluated in dependency order, not in column order
+	tb, err := tb.Reorder("win", "winRate %", "winRate", "loose")
+	assert.NoError(t, err)
+	assert.NoError(t, tb.Update(0, datatable.Row{"win": 10, "loose": 10}))
+	checkTable(t, tb,
+		"win", "winRate %", "winRate", "loose",
+		10, "50 %", 50.0, 10,
+		20, "80 %", 80.0, 5,
+		666, "50 %", 50.0, 666,
+	)
+
+	deps, err := tb.Dependencies("winRate")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"win", "loose"}, deps)
+
+	deps, err = tb.Dependencies("win")
+	assert.NoError(t, err)
+	assert.Empty(t, deps)
+
+	_, err = tb.Dependencies("unknown")
+	assert.Error(t, err)
+}
+
+func TestExprCycle(t *testing.T) {
+	tb := datatable.New("test")
+	assert.NoError(t, tb.AddColumn("c", datatable.Int, datatable.Values(1, 2)))
+	assert.NoError(t, tb.AddColumn("b", datatable.Int, datatable.Expr("`c` + 1")))
+	assert.NoError(t, tb.AddColumn("a", datatable.Int, datatable.Expr("`b` + 1")))
+	tb.RemoveColumn("c")
+	err := tb.AddColumn("c", datatable.Int, datatable.Expr("`a` + 1"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrExprCycle.Error())
+	assert.Error(t, tb.AddColumn("d", datatable.Int, datatable.Expr("`d` + 1")))
+	assert.Nil(t, tb.Column("c"))
+	assert.Nil(t, tb.Column("d"))
+}
+
+func TestExprUnknownReference(t *testing.T) {
+	tb := datatable.New("test")
+	assert.NoError(t, tb.AddColumn("a", datatable.Int, datatable.Values(1, 2)))
+
+	// the references are checked when the column is added
+	err := tb.AddColumn("b", datatable.Int, datatable.Expr("`a` + `c`"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrUnknownColumnRef.Error())
+	assert.Contains(t, err.Error(), "'c'")
+	assert.Nil(t, tb.Column("b"))
+
+	assert.NoError(t, tb.AddColumn("c", datatable.Int, datatable.Values(10, 20)))
+	assert.NoError(t, tb.AddColumn("b", datatable.Int, datatable.Expr("`a` + `c`")))
+	checkTable(t, tb,
+		"a", "c", "b",
+		1, 10, 11,
+		2, 20, 22,
+	)
+}
@@ -0,0 +1,122 @@
+package datatable
+
+import (
+	"reflect"
+
+	"github.com/datasweet/expr"
+)
+
+// The nodes of the expr package are unexported, so they are read with reflection.
+// All the knowledge of the node structs is kept in this file, TestExprNode pins it
+// for the version of expr in go.mod.
+
+var exprPkgPath = reflect.TypeOf((*expr.Node)(nil)).Elem().PkgPath()
+
+// exprNodeKind is the kind of a node of an expression
+type exprNodeKind uint8
+
+const (
+	exprOtherNode    exprNodeKind = iota // any other node, ie an operator
+	exprNameNode                         // column reference, ie `col`
+	exprFunctionNode                     // call of a registered function
+	exprBuiltinNode                      // call of a builtin of expr
+	exprNumberNode                       // number literal
+	exprTextNode                         // string literal
+	exprBoolNode                         // boolean literal
+)
+
+// exprNodeKinds are the struct names of the nodes by kind
+var exprNodeKinds = map[string]exprNodeKind{
+	"nameNode":     exprNameNode,
+	"functionNode": exprFunctionNode,
+	"builtinNode":  exprBuiltinNode,
+	"numberNode":   exprNumberNode,
+	"textNode":     exprTextNode,
+	"boolNode":     exprBoolNode,
+}
+
+// exprNode is a node of an expression
+type exprNode struct {
+	v reflect.Value // struct of the expr package
+}
+
+// newExprNode returns the node of an expression
+func newExprNode(node expr.Node) (exprNode, bool) {
+	return exprNodeOf(reflect.ValueOf(node))
+}
+
+// exprNodeOf returns the node of a value holding a node
+func exprNodeOf(v reflect.Value) (exprNode, bool) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return exprNode{}, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || v.Type().PkgPath() != exprPkgPath {
+		return exprNode{}, false
+	}
+	return exprNode{v: v}, true
+}
+
+// kind returns the kind of the node
+func (n exprNode) kind() exprNodeKind {
+	return exprNodeKinds[n.v.Type().Name()]
+}
+
+// typeName returns the struct name of the node, ie for the errors
+func (n exprNode) typeName() string {
+	return n.v.Type().Name()
+}
+
+// name returns the name of a column reference, a function or a builtin
+func (n exprNode) name() string {
+	switch n.kind() {
+	case exprNameNode, exprFunctionNode, exprBuiltinNode:
+		return n.v.FieldByName("name").String()
+	}
+	return ""
+}
+
+// arguments returns the arguments of a function or a builtin call
+func (n exprNode) arguments() []exprNode {
+	switch n.kind() {
+	case exprFunctionNode, exprBuiltinNode:
+	default:
+		return nil
+	}
+	args := n.v.FieldByName("arguments")
+	nodes := make([]exprNode, 0, args.Len())
+	for i := 0; i < args.Len(); i++ {
+		if arg, ok := exprNodeOf(args.Index(i)); ok {
+			nodes = append(nodes, arg)
+		}
+	}
+	return nodes
+}
+
+// walkExpr visits all nodes of an expression
+func walkExpr(node expr.Node, visit func(n exprNode)) {
+	walkExprValue(reflect.ValueOf(node), visit)
+}
+
+func walkExprValue(v reflect.Value, visit func(n exprNode)) {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if !v.IsNil() {
+			walkExprValue(v.Elem(), visit)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			walkExprValue(v.Index(i), visit)
+		}
+	case reflect.Struct:
+		if v.Type().PkgPath() != exprPkgPath {
+			return
+		}
+		visit(exprNode{v: v})
+		for i := 0; i < v.NumField(); i++ {
+			walkExprValue(v.Field(i), visit)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package datatable
+
+import (
+	"testing"
+
+	"github.com/datasweet/expr"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExprNode pins the node structs of the expr package read by exprNode
+func TestExprNode(t *testing.T) {
+	node, err := expr.Parse("`a` + myfunc(1, \"x\", true, `b`) - sum(`c`)")
+	assert.NoError(t, err)
+
+	type visited struct {
+		kind exprNodeKind
+		name string
+		args int
+	}
+	var nodes []visited
+	walkExpr(node, func(n exprNode) {
+		if n.kind() != exprOtherNode {
+			nodes = append(nodes, visited{n.kind(), n.name(), len(n.arguments())})
+		}
+	})
+	assert.ElementsMatch(t, []visited{
+		{exprNameNode, "a", 0},
+		{exprFunctionNode, "myfunc", 4},
+		{exprNumberNode, "", 0},
+		{exprTextNode, "", 0},
+		{exprBoolNode, "", 0},
+		{exprNameNode, "b", 0},
+		{exprBuiltinNode, "sum", 1},
+		{exprNameNode, "c", 0},
+	}, nodes)
+
+	fn, ok := newExprNode(mustParse(t, "myfunc(1, \"x\", true)"))
+	assert.True(t, ok)
+	assert.Equal(t, exprFunctionNode, fn.kind())
+	args := fn.arguments()
+	if assert.Len(t, args, 3) {
+		assert.Equal(t, exprNumberNode, args[0].kind())
+		assert.Equal(t, exprTextNode, args[1].kind())
+		assert.Equal(t, exprBoolNode, args[2].kind())
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, exprRefs(node))
+	assert.Equal(t, []string{"myfunc"}, exprFunctions(node))
+	assert.True(t, exprAggregated(node))
+	assert.False(t, exprAggregated(mustParse(t, "`a` * 2")))
+	assert.True(t, isExprNode(mustParse(t, "upper(`a`)"), exprBuiltinNode))
+}
+
+func mustParse(t *testing.T, formulae string) expr.Node {
+	node, err := expr.Parse(formulae)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node
+}
@@ -0,0 +1,270 @@
+package datatable
+
+import (
+	"math"
+	"time"
+
+	"github.com/datasweet/cast"
+	"github.com/pkg/errors"
+	"github.com/xinzf/datatable/serie"
+)
+
+// NullMode defines when a row is dropped by DropNullRows
+type NullMode uint8
+
+const (
+	AnyNull NullMode = iota // drops the row if any value is nil
+	AllNull                 // drops the row if all values are nil
+)
+
+// DropNulls returns a copy of the datatable without the rows with a nil value.
+// Rows are checked on {cols}, all visible columns by default.
+func (t *DataTable) DropNulls(cols ...string) (*DataTable, error) {
+	return t.DropNullRows(cols, AnyNull)
+}
+
+// DropNullRows returns a copy of the datatable without the rows with nil values.
+// Rows are checked on {subset}, all visible columns by default.
+func (t *DataTable) DropNullRows(subset []string, mode NullMode) (*DataTable, error) {
+	if len(subset) == 0 {
+		subset = t.Columns()
+	}
+	if err := t.checkColumns(subset...); err != nil {
+		return nil, err
+	}
+	if err := t.evaluateExpressions(); err != nil {
+		return nil, err
+	}
+
+	series := make([]serie.Serie, 0, len(subset))
+	for _, name := range subset {
+		series = append(series, t.cols[t.ColumnIndex(name)].serie)
+	}
+
+	rows := make([]int, 0, t.nrows)
+	for i := 0; i < t.nrows; i++ {
+		nulls := 0
+		for _, sr := range series {
+			if sr.Get(i) == nil {
+				nulls++
+			}
+		}
+		switch {
+		case mode == AnyNull && nulls > 0:
+		case mode == AllNull && nulls > 0 && nulls == len(series):
+		default:
+			rows = append(rows, i)
+		}
+	}
+
+	return t.pick(rows...), nil
+}
+
+// FillNull replaces the nil values of the column with {value}
+// On error, the column is not modified.
+func (t *DataTable) FillNull(name string, value interface{}) error {
+	col, err := t.fillableColumn(name)
+	if err != nil {
+		return err
+	}
+
+	// converts the value before filling any row
+	conv := col.serie.EmptyCopy()
+	conv.Append(value)
+	if conv.Len() != 1 {
+		err := errors.Errorf("column '%s': expected 1 value, got %d", name, conv.Len())
+		return errors.Wrap(err, ErrFillValue.Error())
+	}
+	if conv.Get(0) == nil && value != nil {
+		err := errors.Errorf("column '%s': can't convert %v (%T) to %s", name, value, value, col.typ)
+		return errors.Wrap(err, ErrFillValue.Error())
+	}
+	value = conv.Get(0)
+
+	for i := 0; i < t.nrows; i++ {
+		if col.serie.Get(i) == nil {
+			if err := col.serie.Set(i, value); err != nil {
+				return err
+			}
+		}
+	}
+	t.markColumn(col.name)
+	return nil
+}
+
+// FillForward replaces the nil values of the column with the previous non-nil value.
+// The values are propagated in each group of rows with the same values on {by}, if any.
+func (t *DataTable) FillForward(name string, by ...string) error {
+	return t.fillDirection(name, by, false)
+}
+
+// FillBackward replaces the nil values of the column with the next non-nil value.
+// The values are propagated in each group of rows with the same values on {by}, if any.
+func (t *DataTable) FillBackward(name string, by ...string) error {
+	return t.fillDirection(name, by, true)
+}
+
+func (t *DataTable) fillDirection(name string, by []string, backward bool) error {
+	col, err := t.fillableColumn(name)
+	if err != nil {
+		return err
+	}
+	if err := t.checkColumns(by...); err != nil {
+		return err
+	}
+
+	groups, err := t.fillGroups(by)
+	if err != nil {
+		return err
+	}
+
+	for _, rows := range groups {
+		var last interface{}
+		for k := range rows {
+			i := rows[k]
+			if backward {
+				i = rows[len(rows)-1-k]
+			}
+			v := col.serie.Get(i)
+			if v != nil {
+				last = v
+				continue
+			}
+			if last != nil {
+				if err := col.serie.Set(i, last); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	t.markColumn(col.name)
+	return nil
+}
+
+// Interpolate replaces the nil values of a numeric column with a linear interpolation
+// between the surrounding values, by row position.
+// The leading and trailing nil values are kept.
+func (t *DataTable) Interpolate(name string) error {
+	return t.interpolate(name, func(i int) (float64, bool) {
+		return float64(i), true
+	})
+}
+
+// InterpolateTime replaces the nil values of a numeric column with a linear interpolation
+// between the surrounding values, weighted by the time in column {timeCol}.
+// The leading and trailing nil values, and the rows without time, are kept.
+func (t *DataTable) InterpolateTime(name, timeCol string) error {
+	pos := t.ColumnIndex(timeCol)
+	if pos < 0 {
+		err := errors.Errorf("column '%s' not found", timeCol)
+		return errors.Wrap(err, ErrColumnNotFound.Error())
+	}
+	if typ := t.cols[pos].typ; typ != Time {
+		err := errors.Errorf("column '%s' is not a time column", timeCol)
+		return errors.Wrap(err, ErrColumnTypeMismatch.Error())
+	}
+	if err := t.evaluateExpressions(); err != nil {
+		return err
+	}
+
+	times := t.cols[pos].serie
+	return t.interpolate(name, func(i int) (float64, bool) {
+		tm, ok := times.Get(i).(time.Time)
+		if !ok {
+			return 0, false
+		}
+		return float64(tm.UnixNano()), true
+	})
+}
+
+// interpolate interpolates the nil values with the position given by {at}
+func (t *DataTable) interpolate(name string, at func(i int) (float64, bool)) error {
+	col, err := t.fillableColumn(name)
+	if err != nil {
+		return err
+	}
+
+	var integer bool
+	switch col.typ {
+	case Int, Int32, Int64:
+		integer = true
+	case Float32, Float64:
+	default:
+		err := errors.Errorf("column '%s' is not a numeric column", name)
+		return errors.Wrap(err, ErrColumnTypeMismatch.Error())
+	}
+
+	prev := -1
+	for i := 0; i < t.nrows; i++ {
+		if col.serie.Get(i) == nil {
+			continue
+		}
+		if prev >= 0 && i-prev > 1 {
+			x0, ok0 := at(prev)
+			x1, ok1 := at(i)
+			y0, _ := cast.AsFloat64(col.serie.Get(prev))
+			y1, _ := cast.AsFloat64(col.serie.Get(i))
+
+			for j := prev + 1; j < i; j++ {
+				x, ok := at(j)
+				if !ok || !ok0 || !ok1 || x1 == x0 {
+					continue
+				}
+				y := y0 + (y1-y0)*(x-x0)/(x1-x0)
+				if integer {
+					y = math.Round(y)
+				}
+				if err := col.serie.Set(j, y); err != nil {
+					return err
+				}
+			}
+		}
+		prev = i
+	}
+	t.markColumn(col.name)
+	return nil
+}
+
+// fillableColumn returns the column to fill
+// The values are evaluated, a computed column can't be filled.
+func (t *DataTable) fillableColumn(name string) (*column, error) {
+	pos := t.ColumnIndex(name)
+	if pos < 0 {
+		err := errors.Errorf("column '%s' not found", name)
+		return nil, errors.Wrap(err, ErrColumnNotFound.Error())
+	}
+	col := t.cols[pos]
+	if col.IsComputed() {
+		err := errors.Errorf("column '%s' is computed", name)
+		return nil, errors.Wrap(err, ErrComputedColumn.Error())
+	}
+	if err := t.evaluateExpressions(); err != nil {
+		return nil, err
+	}
+	return col, nil
+}
+
+// fillGroups returns the rows by group, in order
+func (t *DataTable) fillGroups(by []string) ([][]int, error) {
+	if len(by) == 0 {
+		return [][]int{allRows(t.nrows)}, nil
+	}
+
+	keys, err := t.groupKeys(by)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups [][]int
+	index := make(map[int]int)
+	for i, key := range keys {
+		g, ok := index[key]
+		if !ok {
+			g = len(groups)
+			index[key] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups, nil
+}
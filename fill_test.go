@@ -0,0 +1,153 @@
+package datatable_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func sampleForFill() *datatable.DataTable {
+	tb := datatable.New("test")
+	tb.AddColumn("region", datatable.String, datatable.Values("north", "south", "north", "south", "north", "south"))
+	tb.AddColumn("date", datatable.Time, datatable.Values("2020-01-01", "2020-01-01", "2020-01-02", "2020-01-04", "2020-01-05", nil))
+	tb.AddColumn("amount", datatable.Int, datatable.Values(10, nil, nil, 20, 40, nil))
+	tb.AddColumn("double", datatable.Int, datatable.Expr("`amount` * 2"))
+	return tb
+}
+
+func TestFillNull(t *testing.T) {
+	tb := sampleForFill()
+
+	assert.NoError(t, tb.FillNull("amount", 0))
+	dt, err := tb.Select("amount", "double")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"amount", "double",
+		10, 20,
+		0, 0,
+		0, 0,
+		20, 40,
+		40, 80,
+		0, 0,
+	)
+
+	assert.Error(t, tb.FillNull("double", 0))
+	assert.Error(t, tb.FillNull("unknown", 0))
+
+	// a slice can't fill a cell, no row is filled
+	tb = sampleForFill()
+	err = tb.FillNull("amount", []int{1, 2})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrFillValue.Error())
+	assert.Equal(t, []interface{}{10, nil, nil, 20, 40, nil}, tb.Column("amount").Serie().All())
+
+	// a value which can't be converted is not a nil fill
+	err = tb.FillNull("amount", "abc")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrFillValue.Error())
+	assert.Equal(t, []interface{}{10, nil, nil, 20, 40, nil}, tb.Column("amount").Serie().All())
+}
+
+func TestFillForwardBackward(t *testing.T) {
+	tb := sampleForFill()
+	assert.NoError(t, tb.FillForward("amount"))
+	dt, err := tb.Select("amount", "double")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"amount", "double",
+		10, 20,
+		10, 20,
+		10, 20,
+		20, 40,
+		40, 80,
+		40, 80,
+	)
+
+	tb = sampleForFill()
+	assert.NoError(t, tb.FillForward("amount", "region"))
+	dt, err = tb.Select("region", "amount")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"region", "amount",
+		"north", 10,
+		"south", nil,
+		"north", 10,
+		"south", 20,
+		"north", 40,
+		"south", 20,
+	)
+
+	tb = sampleForFill()
+	assert.NoError(t, tb.FillBackward("amount", "region"))
+	dt, err = tb.Select("region", "amount")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"region", "amount",
+		"north", 10,
+		"south", 20,
+		"north", 40,
+		"south", 20,
+		"north", 40,
+		"south", nil,
+	)
+
+	assert.Error(t, tb.FillForward("amount", "unknown"))
+}
+
+func TestDropNulls(t *testing.T) {
+	tb := sampleForFill()
+
+	dt, err := tb.DropNulls()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, dt.NumRows())
+
+	dt, err = tb.DropNullRows([]string{"date", "amount"}, datatable.AllNull)
+	assert.NoError(t, err)
+	dt, err = dt.Select("region", "amount")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"region", "amount",
+		"north", 10,
+		"south", nil,
+		"north", nil,
+		"south", 20,
+		"north", 40,
+	)
+
+	_, err = tb.DropNulls("unknown")
+	assert.Error(t, err)
+}
+
+func TestInterpolate(t *testing.T) {
+	tb := sampleForFill()
+	assert.NoError(t, tb.Interpolate("amount"))
+	dt, err := tb.Select("amount", "double")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"amount", "double",
+		10, 20,
+		13, 26,
+		17, 34,
+		20, 40,
+		40, 80,
+		nil, nil,
+	)
+
+	tb = sampleForFill()
+	assert.NoError(t, tb.InterpolateTime("amount", "date"))
+	dt, err = tb.Select("amount")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"amount",
+		10,
+		10,
+		13,
+		20,
+		40,
+		nil,
+	)
+
+	assert.Error(t, tb.Interpolate("region"))
+	assert.Error(t, tb.InterpolateTime("amount", "region"))
+}
@@ -0,0 +1,305 @@
+package datatable
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/datasweet/expr"
+	"github.com/pkg/errors"
+)
+
+// function is a go func callable in the expressions
+type function struct {
+	name string
+	fn   reflect.Value
+}
+
+// functions is our function registry, guarded by functionsMu
+var (
+	functions   = make(map[string]*function)
+	functionsMu sync.RWMutex
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// RegisterFunction to extends the functions usable in the expressions of the computed columns
+// fn must be a func returning one value, or one value and an error.
+// The function is called for each row with the values of the row:
+// - the arguments are converted to the parameter types of fn,
+// - a nil argument results in a nil value, unless the parameter accepts nil (interface, pointer, ...).
+// In a datatable, a column can't have the name of a function called by an expression.
+func RegisterFunction(name string, fn interface{}) error {
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		return ErrEmptyName
+	}
+	if fn == nil {
+		return ErrNilFunction
+	}
+
+	v := reflect.ValueOf(fn)
+	typ := v.Type()
+	if typ.Kind() != reflect.Func {
+		err := errors.Errorf("function '%s': expected a func, got %T", name, fn)
+		return errors.Wrap(err, ErrInvalidFunction.Error())
+	}
+	if typ.NumOut() == 0 || typ.NumOut() > 2 || (typ.NumOut() == 2 && typ.Out(1) != errorType) {
+		err := errors.Errorf("function '%s': must return one value, or one value and an error", name)
+		return errors.Wrap(err, ErrInvalidFunction.Error())
+	}
+
+	// the name must be parsed as a function call, not a builtin
+	node, err := expr.Parse(name + "()")
+	if err != nil || !isExprNode(node, exprFunctionNode) {
+		err := errors.Errorf("function '%s': invalid name or builtin", name)
+		return errors.Wrap(err, ErrInvalidFunction.Error())
+	}
+
+	functionsMu.Lock()
+	defer functionsMu.Unlock()
+	if _, ok := functions[name]; ok {
+		err := errors.Errorf("function '%s' already exists", name)
+		return errors.Wrap(err, ErrFunctionAlreadyExists.Error())
+	}
+	functions[name] = &function{name: name, fn: v}
+	return nil
+}
+
+// Functions to list all registered functions
+func Functions() []string {
+	functionsMu.RLock()
+	defer functionsMu.RUnlock()
+	names := make([]string, 0, len(functions))
+	for k := range functions {
+		names = append(names, k)
+	}
+	return names
+}
+
+// lookupFunction returns the registered function with name
+func lookupFunction(name string) (*function, bool) {
+	functionsMu.RLock()
+	defer functionsMu.RUnlock()
+	fn, ok := functions[name]
+	return fn, ok
+}
+
+// isExprNode checks the kind of an expr node
+func isExprNode(node expr.Node, kind exprNodeKind) bool {
+	n, ok := newExprNode(node)
+	return ok && n.kind() == kind
+}
+
+// in returns the type of the parameter at index
+func (f *function) in(at int) reflect.Type {
+	typ := f.fn.Type()
+	if typ.IsVariadic() && at >= typ.NumIn()-1 {
+		return typ.In(typ.NumIn() - 1).Elem()
+	}
+	return typ.In(at)
+}
+
+// checkArity checks the number of arguments
+func (f *function) checkArity(n int) error {
+	typ := f.fn.Type()
+	if typ.IsVariadic() && n < typ.NumIn()-1 {
+		err := errors.Errorf("function '%s': wrong count of arguments, expected at least %d, got %d", f.name, typ.NumIn()-1, n)
+		return errors.Wrap(err, ErrFunctionCall.Error())
+	}
+	if !typ.IsVariadic() && n != typ.NumIn() {
+		err := errors.Errorf("function '%s': wrong count of arguments, expected %d, got %d", f.name, typ.NumIn(), n)
+		return errors.Wrap(err, ErrFunctionCall.Error())
+	}
+	return nil
+}
+
+// checkArgs checks the number of arguments and the type of the literals
+func (f *function) checkArgs(args []exprNode) error {
+	n := len(args)
+	if err := f.checkArity(n); err != nil {
+		return err
+	}
+
+	for i := 0; i < n; i++ {
+		arg := args[i]
+		var ok bool
+		switch kind := f.in(i).Kind(); arg.kind() {
+		case exprNumberNode:
+			ok = kind == reflect.Interface || isNumberKind(kind)
+		case exprTextNode:
+			ok = kind == reflect.Interface || kind == reflect.String
+		case exprBoolNode:
+			ok = kind == reflect.Interface || kind == reflect.Bool
+		default:
+			ok = true
+		}
+		if !ok {
+			err := errors.Errorf("function '%s': argument %d: can't use %s as %s", f.name, i+1, arg.typeName(), f.in(i))
+			return errors.Wrap(err, ErrFunctionCall.Error())
+		}
+	}
+	return nil
+}
+
+// exprCalls records the first error of the function calls of an evaluation.
+// expr only keeps the message of an error raised in a function, the error is returned after expr.Run.
+type exprCalls struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (c *exprCalls) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// check returns the recorded error, if any, or err
+func (c *exprCalls) check(err error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	return err
+}
+
+// bind returns the function to call in an evaluation, the errors are recorded in calls
+func (f *function) bind(calls *exprCalls) func(args ...interface{}) interface{} {
+	return func(args ...interface{}) interface{} {
+		res, err := f.call(args...)
+		if err != nil {
+			calls.fail(err)
+			return nil
+		}
+		return res
+	}
+}
+
+// call calls the function element-wise
+// If an argument is an array (ie a column), the function is called for each row.
+func (f *function) call(args ...interface{}) (interface{}, error) {
+	if err := f.checkArity(len(args)); err != nil {
+		return nil, err
+	}
+
+	size := -1
+	for _, arg := range args {
+		if arr, ok := arg.([]interface{}); ok && len(arr) > size {
+			size = len(arr)
+		}
+	}
+
+	if size < 0 {
+		return f.callAt(args, -1)
+	}
+
+	out := make([]interface{}, size)
+	for i := range out {
+		v, err := f.callAt(args, i)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// callAt calls the function with the values at row
+func (f *function) callAt(args []interface{}, row int) (interface{}, error) {
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		if arr, ok := arg.([]interface{}); ok && row >= 0 {
+			arg = nil
+			if row < len(arr) {
+				arg = arr[row]
+			}
+		}
+
+		typ := f.in(i)
+		if arg == nil {
+			switch typ.Kind() {
+			case reflect.Interface, reflect.Ptr, reflect.Slice, reflect.Map:
+				in[i] = reflect.Zero(typ)
+				continue
+			}
+			return nil, nil
+		}
+
+		v := reflect.ValueOf(arg)
+		switch {
+		case v.Type().AssignableTo(typ):
+			in[i] = v
+		case isNumberKind(v.Kind()) && isNumberKind(typ.Kind()):
+			in[i] = v.Convert(typ)
+		default:
+			err := errors.Errorf("function '%s': argument %d: can't use %T as %s", f.name, i+1, arg, typ)
+			return nil, errors.Wrap(err, ErrFunctionCall.Error())
+		}
+	}
+
+	out := f.fn.Call(in)
+	if len(out) == 2 && !out[1].IsNil() {
+		return nil, errors.Wrapf(out[1].Interface().(error), "function '%s'", f.name)
+	}
+	return out[0].Interface(), nil
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// checkFunctionColumns checks that no column of the datatable has the name of a function called by the expression,
+// a column hides a function with the same name when the expression is evaluated.
+func (t *DataTable) checkFunctionColumns(node expr.Node) error {
+	for _, name := range exprFunctions(node) {
+		if t.ColumnIndex(name) >= 0 {
+			err := errors.Errorf("function '%s' is hidden by the column '%s'", name, name)
+			return errors.Wrap(err, ErrFunctionColumnClash.Error())
+		}
+	}
+	return nil
+}
+
+// checkColumnFunctions checks that no expression of the datatable calls a function named as the column {name}
+func (t *DataTable) checkColumnFunctions(name string) error {
+	for _, col := range t.cols {
+		if col.expr == nil {
+			continue
+		}
+		for _, fn := range exprFunctions(col.expr) {
+			if fn == name {
+				err := errors.Errorf("column '%s' hides the function called by the column '%s'", name, col.name)
+				return errors.Wrap(err, ErrFunctionColumnClash.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// checkFunctions checks the function calls of an expression
+func checkFunctions(node expr.Node) error {
+	var err error
+	walkExpr(node, func(n exprNode) {
+		if err != nil || n.kind() != exprFunctionNode {
+			return
+		}
+		name := n.name()
+		fn, ok := lookupFunction(name)
+		if !ok {
+			err = errors.Wrap(errors.Errorf("function '%s' not found", name), ErrUnknownFunction.Error())
+			return
+		}
+		err = fn.checkArgs(n.arguments())
+	})
+	return err
+}
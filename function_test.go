@@ -0,0 +1,154 @@
+package datatable_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+var errNegative = errors.New("negative value")
+
+func init() {
+	_ = datatable.RegisterFunction("convert", func(amount float64, currency string) float64 {
+		if currency == "EUR" {
+			return amount * 2
+		}
+		return amount
+	})
+	_ = datatable.RegisterFunction("initials", func(names ...string) string {
+		var sb strings.Builder
+		for _, n := range names {
+			if len(n) > 0 {
+				sb.WriteByte(n[0])
+			}
+		}
+		return sb.String()
+	})
+	_ = datatable.RegisterFunction("joinwith", func(sep string, parts ...string) string {
+		return strings.Join(parts, sep)
+	})
+	_ = datatable.RegisterFunction("checked", func(v int) (int, error) {
+		if v < 0 {
+			return 0, errNegative
+		}
+		return v, nil
+	})
+}
+
+func TestRegisterFunction(t *testing.T) {
+	assert.Error(t, datatable.RegisterFunction("", func() int { return 0 }))
+	assert.Error(t, datatable.RegisterFunction("nilfunc", nil))
+	assert.Error(t, datatable.RegisterFunction("notafunc", 10))
+	assert.Error(t, datatable.RegisterFunction("noresult", func() {}))
+	assert.Error(t, datatable.RegisterFunction("noerror", func() (int, int) { return 0, 0 }))
+	assert.Error(t, datatable.RegisterFunction("sum", func(v int) int { return v }))
+	assert.Error(t, datatable.RegisterFunction("convert", func(v int) int { return v }))
+	assert.Contains(t, datatable.Functions(), "convert")
+}
+
+// concurrentRuns makes the registered names unique with -count
+var concurrentRuns int32
+
+func TestRegisterFunctionConcurrent(t *testing.T) {
+	run := atomic.AddInt32(&concurrentRuns, 1)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, datatable.RegisterFunction(fmt.Sprintf("concurrent%d_%d", run, i), func(v int) int { return v }))
+			assert.NotEmpty(t, datatable.Functions())
+			tb := datatable.New("test")
+			assert.NoError(t, tb.AddColumn("amount", datatable.Int, datatable.Values(10, 20)))
+			assert.NoError(t, tb.AddColumn("converted", datatable.Float64, datatable.Expr("convert(`amount`, \"EUR\")")))
+			assert.NoError(t, tb.Evaluate())
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestExprWithFunction(t *testing.T) {
+	tb := datatable.New("test")
+	assert.NoError(t, tb.AddColumn("amount", datatable.Int, datatable.Values(10, 20, nil)))
+	assert.NoError(t, tb.AddColumn("currency", datatable.String, datatable.Values("EUR", "USD", "EUR")))
+	assert.NoError(t, tb.AddColumn("first", datatable.String, datatable.Values("John", "Jane", "Bob")))
+	assert.NoError(t, tb.AddColumn("last", datatable.String, datatable.Values("Doe", "Smith", "Marley")))
+	assert.NoError(t, tb.AddColumn("converted", datatable.Float64, datatable.Expr("convert(`amount`, `currency`)")))
+	assert.NoError(t, tb.AddColumn("fixed", datatable.Float64, datatable.Expr("convert(`amount`, \"EUR\") + 1")))
+	assert.NoError(t, tb.AddColumn("abbr", datatable.String, datatable.Expr("initials(`first`, `last`)")))
+	checkTable(t, tb,
+		"amount", "currency", "first", "last", "converted", "fixed", "abbr",
+		10, "EUR", "John", "Doe", 20.0, 21.0, "JD",
+		20, "USD", "Jane", "Smith", 20.0, 41.0, "JS",
+		nil, "EUR", "Bob", "Marley", nil, nil, "BM",
+	)
+}
+
+func TestExprWithFunctionErrors(t *testing.T) {
+	tb := datatable.New("test")
+	assert.NoError(t, tb.AddColumn("amount", datatable.Int, datatable.Values(10, -1)))
+
+	err := tb.AddColumn("a", datatable.Int, datatable.Expr("unknown(`amount`)"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrUnknownFunction.Error())
+
+	err = tb.AddColumn("b", datatable.Int, datatable.Expr("convert(`amount`)"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrFunctionCall.Error())
+
+	err = tb.AddColumn("c", datatable.Int, datatable.Expr("convert(`amount`, 10)"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrFunctionCall.Error())
+
+	err = tb.AddColumn("d", datatable.Int, datatable.Expr("initials()"))
+	assert.NoError(t, err)
+
+	err = tb.AddColumn("f", datatable.String, datatable.Expr("joinwith()"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expected at least 1, got 0")
+
+	assert.NoError(t, tb.AddColumn("e", datatable.Int, datatable.Expr("checked(`amount`)")))
+	err = tb.Evaluate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "negative value")
+	assert.Equal(t, errNegative, errors.Cause(err))
+
+	tb = datatable.New("test")
+	assert.NoError(t, tb.AddColumn("amount", datatable.Int, datatable.Values(10, -1)))
+	_, err = tb.WhereExpr("checked(`amount`) > 0")
+	assert.Equal(t, errNegative, errors.Cause(err))
+}
+
+func TestExprFunctionColumnClash(t *testing.T) {
+	// a column named as a called function
+	tb := datatable.New("test")
+	assert.NoError(t, tb.AddColumn("amount", datatable.Int, datatable.Values(10, 20)))
+	assert.NoError(t, tb.AddColumn("convert", datatable.Float64, datatable.Values(1.0, 2.0)))
+	err := tb.AddColumn("converted", datatable.Float64, datatable.Expr("convert(`amount`, \"EUR\")"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrFunctionColumnClash.Error())
+	err = tb.AddColumn("initials", datatable.String, datatable.Expr("initials(\"a\")"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrFunctionColumnClash.Error())
+	_, err = tb.WhereExpr("convert(`amount`, \"EUR\") > 10")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrFunctionColumnClash.Error())
+
+	// a function called by an expression
+	tb = datatable.New("test")
+	assert.NoError(t, tb.AddColumn("amount", datatable.Int, datatable.Values(10, 20)))
+	assert.NoError(t, tb.AddColumn("converted", datatable.Float64, datatable.Expr("convert(`amount`, \"EUR\")")))
+	err = tb.AddColumn("convert", datatable.Float64, datatable.Values(1.0, 2.0))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrFunctionColumnClash.Error())
+	err = tb.RenameColumn("amount", "convert")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrFunctionColumnClash.Error())
+	assert.Equal(t, []string{"amount", "converted"}, tb.Columns())
+}
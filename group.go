@@ -0,0 +1,198 @@
+package datatable
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/xinzf/datatable/serie"
+)
+
+// subset creates new groups with the same configuration
+func (g *Groups) subset(groups []*group) *Groups {
+	return &Groups{dt: g.dt, by: g.by, groups: groups}
+}
+
+// table creates the datatable with the rows of a group
+func (g *Groups) table(gr *group) *DataTable {
+	if gr.TakeAll {
+		return g.dt.Copy()
+	}
+	return g.dt.pick(gr.Rows...)
+}
+
+// Len returns the number of groups
+func (g *Groups) Len() int {
+	if g == nil {
+		return 0
+	}
+	return len(g.groups)
+}
+
+// Keys returns a copy of the keys of each group
+func (g *Groups) Keys() [][]interface{} {
+	if g == nil {
+		return nil
+	}
+	keys := make([][]interface{}, 0, len(g.groups))
+	for _, gr := range g.groups {
+		key := make([]interface{}, len(gr.Buckets))
+		copy(key, gr.Buckets)
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Get returns the rows of the group with keys
+// The keys must have the same types as the group keys, returns nil if not found
+func (g *Groups) Get(keys ...interface{}) *DataTable {
+	if g == nil {
+		return nil
+	}
+	hash := hashKeys(keys)
+	for _, gr := range g.groups {
+		if !gr.TakeAll && gr.Key == hash && reflect.DeepEqual(gr.Buckets, keys) {
+			return g.table(gr)
+		}
+	}
+	return nil
+}
+
+// Each calls fn for each group with the group keys and the group rows.
+// Each stops at the first error.
+func (g *Groups) Each(fn func(keys []interface{}, sub *DataTable) error) error {
+	if g == nil {
+		return ErrNoGroups
+	}
+	if fn == nil {
+		return nil
+	}
+	for _, gr := range g.groups {
+		if err := fn(gr.Buckets, g.table(gr)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply calls fn on the rows of each group and concats the results
+func (g *Groups) Apply(fn func(sub *DataTable) (*DataTable, error)) (*DataTable, error) {
+	if g == nil {
+		return nil, ErrNoGroups
+	}
+	if g.dt == nil {
+		return nil, ErrNilDatatable
+	}
+	if fn == nil {
+		return g.dt.EmptyCopy(), nil
+	}
+
+	tables := make([]*DataTable, 0, len(g.groups))
+	for _, gr := range g.groups {
+		out, err := fn(g.table(gr))
+		if err != nil {
+			return nil, err
+		}
+		if out != nil {
+			tables = append(tables, out)
+		}
+	}
+
+	if len(tables) == 0 {
+		return g.dt.EmptyCopy(), nil
+	}
+	return Concat(tables)
+}
+
+// Having filters the groups on their aggregated values, as the SQL HAVING clause.
+// The predicate receives a row with the group by keys and the aggregations.
+// A nil predicate keeps all the groups.
+func (g *Groups) Having(predicate func(row Row) bool, aggs ...AggregateBy) (*Groups, error) {
+	if g == nil {
+		return nil, ErrNoGroups
+	}
+	if predicate == nil {
+		return g.subset(g.groups), nil
+	}
+
+	summary, owners, err := g.aggregate(aggs...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := summary.RowsE(ExportHidden(true))
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*group
+	for i, row := range rows {
+		if predicate(row) {
+			groups = append(groups, owners[i])
+		}
+	}
+	return g.subset(groups), nil
+}
+
+// Filter filters the groups with a predicate on the group keys and the group rows
+// A nil predicate keeps all the groups.
+func (g *Groups) Filter(predicate func(keys []interface{}, rows *DataTable) bool) *Groups {
+	if g == nil {
+		return nil
+	}
+	if predicate == nil {
+		return g.subset(g.groups)
+	}
+
+	var groups []*group
+	for _, gr := range g.groups {
+		if predicate(gr.Buckets, g.table(gr)) {
+			groups = append(groups, gr)
+		}
+	}
+	return g.subset(groups)
+}
+
+// Top selects the {n} first groups sorted by a group by key or an aggregation
+func (g *Groups) Top(n int, by SortBy, aggs ...AggregateBy) (*Groups, error) {
+	if g == nil {
+		return nil, ErrNoGroups
+	}
+
+	summary, owners, err := g.aggregate(aggs...)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := summary.ColumnIndex(by.Column)
+	if pos < 0 {
+		err := errors.Errorf("column '%s' not found", by.Column)
+		return nil, errors.Wrap(err, ErrColumnNotFound.Error())
+	}
+	sr := summary.cols[pos].serie
+
+	indexes := make([]int, len(owners))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		cmp := sr.Compare(indexes[i], indexes[j])
+		if by.Desc {
+			return cmp == serie.Gt
+		}
+		return cmp == serie.Lt
+	})
+
+	if n < 0 {
+		n = 0
+	}
+	if n > len(indexes) {
+		n = len(indexes)
+	}
+
+	groups := make([]*group, 0, n)
+	for _, idx := range indexes[:n] {
+		groups = append(groups, owners[idx])
+	}
+	return g.subset(groups), nil
+}
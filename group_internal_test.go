@@ -0,0 +1,50 @@
+package datatable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupsMissingSummaryRow(t *testing.T) {
+	dt := New("keys")
+	dt.AddColumn("key", Int, Values(1, 2, 2))
+
+	// the first group has no bucket, so its summary row can't be appended
+	g := &Groups{
+		dt: dt,
+		by: []GroupBy{{Name: "key"}},
+		groups: []*group{
+			{Key: 1, Rows: []int{0}},
+			{Key: 2, Buckets: []interface{}{2}, Rows: []int{1, 2}},
+		},
+	}
+
+	having, err := g.Having(func(row Row) bool { return row.Get("key") == 2 })
+	assert.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{2}}, having.Keys())
+
+	top, err := g.Top(1, SortBy{Column: "count_key", Desc: true}, AggregateBy{Type: Count, Field: "key"})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{2}}, top.Keys())
+}
+
+func TestGroupsGetSameHash(t *testing.T) {
+	dt := New("keys")
+	dt.AddColumn("key", String, Values("a", "b"))
+
+	// both groups have the same hash
+	g := &Groups{
+		dt: dt,
+		by: []GroupBy{{Name: "key"}},
+		groups: []*group{
+			{Key: hashKeys([]interface{}{"b"}), Buckets: []interface{}{"a"}, Rows: []int{0}},
+			{Key: hashKeys([]interface{}{"b"}), Buckets: []interface{}{"b"}, Rows: []int{1}},
+		},
+	}
+
+	b := g.Get("b")
+	assert.NotNil(t, b)
+	assert.Equal(t, []interface{}{"b"}, b.Column("key").Serie().All())
+	assert.Nil(t, g.Get("c"))
+}
@@ -30,3 +30,163 @@ func TestGroup(t *testing.T) {
 	assert.NotNil(t, newDt)
 	newDt.Preview()
 }
+
+func sampleForGroups() *datatable.DataTable {
+	dt := datatable.New("sales")
+	dt.AddColumn("region", datatable.String, datatable.Values("north", "north", "south", "south", "south", "east"))
+	dt.AddColumn("city", datatable.String, datatable.Values("lille", "amiens", "nice", "nice", "marseille", "metz"))
+	dt.AddColumn("amount", datatable.Int, datatable.Values(10, 20, 5, 15, 30, 8))
+	return dt
+}
+
+func groupByRegion(t *testing.T, dt *datatable.DataTable) *datatable.Groups {
+	groups, err := dt.GroupBy(datatable.GroupBy{
+		Name: "region",
+		Keyer: func(row datatable.Row) (interface{}, bool) {
+			return row.Get("region"), true
+		},
+	})
+	assert.NoError(t, err)
+	return groups
+}
+
+func TestGroupHaving(t *testing.T) {
+	groups := groupByRegion(t, sampleForGroups())
+
+	having, err := groups.Having(func(row datatable.Row) bool {
+		return row.Get("sum_amount").(float64) >= 30
+	}, datatable.AggregateBy{Type: datatable.Sum, Field: "amount"})
+	assert.NoError(t, err)
+
+	out, err := having.Aggregate(datatable.AggregateBy{Type: datatable.Count, Field: "amount"})
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"region", "count_amount",
+		"north", int64(2),
+		"south", int64(3),
+	)
+
+	_, err = groups.Having(func(row datatable.Row) bool { return true }, datatable.AggregateBy{Type: datatable.Sum, Field: "unknown"})
+	assert.Error(t, err)
+
+	// a nil predicate keeps all the groups
+	having, err = groups.Having(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, groups.Keys(), having.Keys())
+}
+
+func TestGroupFilter(t *testing.T) {
+	groups := groupByRegion(t, sampleForGroups())
+
+	filtered := groups.Filter(func(keys []interface{}, rows *datatable.DataTable) bool {
+		return keys[0] != "east" && rows.NumRows() < 3
+	})
+
+	out, err := filtered.Aggregate(datatable.AggregateBy{Type: datatable.Sum, Field: "amount"})
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"region", "sum_amount",
+		"north", 30.0,
+	)
+
+	// a nil predicate keeps all the groups
+	assert.Equal(t, groups.Keys(), groups.Filter(nil).Keys())
+}
+
+func TestGroupTop(t *testing.T) {
+	groups := groupByRegion(t, sampleForGroups())
+
+	top, err := groups.Top(2, datatable.SortBy{Column: "sum_amount", Desc: true}, datatable.AggregateBy{Type: datatable.Sum, Field: "amount"})
+	assert.NoError(t, err)
+
+	out, err := top.Aggregate(datatable.AggregateBy{Type: datatable.Max, Field: "amount"})
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"region", "max_amount",
+		"south", 30.0,
+		"north", 20.0,
+	)
+
+	top, err = groups.Top(1, datatable.SortBy{Column: "region"})
+	assert.NoError(t, err)
+	out, err = top.Aggregate()
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"region",
+		"east",
+	)
+
+	_, err = groups.Top(1, datatable.SortBy{Column: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestGroupAccess(t *testing.T) {
+	groups := groupByRegion(t, sampleForGroups())
+
+	assert.Equal(t, 3, groups.Len())
+	assert.Equal(t, [][]interface{}{{"north"}, {"south"}, {"east"}}, groups.Keys())
+
+	// the keys are copies
+	keys := groups.Keys()
+	keys[1][0] = "west"
+	assert.NotNil(t, groups.Get("south"))
+	assert.Equal(t, [][]interface{}{{"north"}, {"south"}, {"east"}}, groups.Keys())
+
+	south := groups.Get("south")
+	assert.NotNil(t, south)
+	checkTable(t, south,
+		"region", "city", "amount",
+		"south", "nice", 5,
+		"south", "nice", 15,
+		"south", "marseille", 30,
+	)
+	assert.Nil(t, groups.Get("west"))
+
+	var sizes []int
+	assert.NoError(t, groups.Each(func(keys []interface{}, sub *datatable.DataTable) error {
+		sizes = append(sizes, sub.NumRows())
+		return nil
+	}))
+	assert.Equal(t, []int{2, 3, 1}, sizes)
+
+	assert.Error(t, groups.Each(func(keys []interface{}, sub *datatable.DataTable) error {
+		return datatable.ErrNoGroups
+	}))
+}
+
+func TestGroupNilKeys(t *testing.T) {
+	dt := datatable.New("pairs")
+	dt.AddColumn("a", datatable.String, datatable.Values("x", nil, "x"))
+	dt.AddColumn("b", datatable.String, datatable.Values(nil, "x", nil))
+
+	keyer := func(col string) func(row datatable.Row) (interface{}, bool) {
+		return func(row datatable.Row) (interface{}, bool) {
+			v := row.Get(col)
+			return v, v != nil
+		}
+	}
+	groups, err := dt.GroupBy(
+		datatable.GroupBy{Name: "a", Keyer: keyer("a")},
+		datatable.GroupBy{Name: "b", Keyer: keyer("b")},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{"x", nil}, {nil, "x"}}, groups.Keys())
+	assert.Equal(t, 2, groups.Get("x", nil).NumRows())
+	assert.Equal(t, 1, groups.Get(nil, "x").NumRows())
+}
+
+func TestGroupApply(t *testing.T) {
+	groups := groupByRegion(t, sampleForGroups())
+
+	// top 1 amount per region
+	out, err := groups.Apply(func(sub *datatable.DataTable) (*datatable.DataTable, error) {
+		return sub.Sort(datatable.SortBy{Column: "amount", Desc: true}).Head(1), nil
+	})
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"region", "city", "amount",
+		"north", "amiens", 20,
+		"south", "marseille", 30,
+		"east", "metz", 8,
+	)
+}
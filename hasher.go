@@ -19,20 +19,29 @@ func (h *hasherImpl) Row(row Row, cols []string) uint64 {
 	enc := gob.NewEncoder(buff)
 
 	for _, name := range cols {
-		enc.Encode(row[name])
+		// gob can't encode nil: flag the presence of the value
+		v := row[name]
+		enc.Encode(v != nil)
+		if v != nil {
+			enc.Encode(v)
+		}
 	}
 
 	return xxhash.Sum64(buff.Bytes())
 }
 
-func (h *hasherImpl) Table(dt *DataTable, cols []string) map[uint64][]int {
+func (h *hasherImpl) Table(dt *DataTable, cols []string) (map[uint64][]int, error) {
 	if dt == nil {
-		return nil
+		return nil, nil
+	}
+	rows, err := dt.RowsE(ExportHidden(true))
+	if err != nil {
+		return nil, err
 	}
 	mh := make(map[uint64][]int, 0)
-	for i, row := range dt.Rows() {
+	for i, row := range rows {
 		hash := h.Row(row, cols)
 		mh[hash] = append(mh[hash], i)
 	}
-	return mh
+	return mh, nil
 }
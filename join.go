@@ -137,6 +137,7 @@ func (jc *joinClause) copyColumnsTo(out *DataTable) error {
 		mon[o] = true
 	}
 
+	ccols := make([]*column, 0, len(jc.table.cols))
 	for _, col := range jc.table.cols {
 		name := col.name
 		cname := name
@@ -157,19 +158,22 @@ func (jc *joinClause) copyColumnsTo(out *DataTable) error {
 
 		ccpy := col.emptyCopy()
 		ccpy.name = cname
-		if err := out.addColumn(ccpy); err != nil {
-			return err
-		}
+		ccols = append(ccols, ccpy)
 
 		jc.cmapper = append(jc.cmapper, [2]string{name, cname})
 	}
 
-	return nil
+	return out.addColumnsInOrder(ccols)
 }
 
-func (jc *joinClause) initHashTable() {
-	jc.hashtable = hasher.Table(jc.table, jc.on)
+func (jc *joinClause) initHashTable() error {
+	hashtable, err := hasher.Table(jc.table, jc.on)
+	if err != nil {
+		return err
+	}
+	jc.hashtable = hashtable
 	jc.consumed = make(map[int]bool, jc.table.NumRows())
+	return nil
 }
 
 type joinImpl struct {
@@ -303,17 +307,27 @@ func (j *joinImpl) join(left, right *DataTable) (*DataTable, error) {
 		return nil, errors.Wrap(err, ErrUnknownMode.Error())
 	}
 
-	join.initHashTable()
+	if err := join.initHashTable(); err != nil {
+		return nil, err
+	}
+
+	refrows, err := ref.table.RowsE(ExportHidden(true))
+	if err != nil {
+		return nil, err
+	}
 
 	// Copy rows
-	for _, refrow := range ref.table.Rows(ExportHidden(true)) {
+	for _, refrow := range refrows {
 		// Create hash
 		hash := hasher.Row(refrow, ref.on)
 
 		// Have we same hash in jointable ?
 		if indexes, ok := join.hashtable[hash]; ok {
 			for _, idx := range indexes {
-				joinrow := join.table.Row(idx, ExportHidden(true))
+				joinrow, err := join.table.RowE(idx, ExportHidden(true))
+				if err != nil {
+					return nil, err
+				}
 				row := out.NewRow()
 				for _, cm := range ref.cmapper {
 					row[cm[1]] = refrow.Get(cm[0])
@@ -336,7 +350,11 @@ func (j *joinImpl) join(left, right *DataTable) (*DataTable, error) {
 
 	// Outer: we must copy rows not consummed in right (join) table
 	if j.mode == outerJoin {
-		for i, joinrow := range join.table.Rows() {
+		joinrows, err := join.table.RowsE()
+		if err != nil {
+			return nil, err
+		}
+		for i, joinrow := range joinrows {
 			if b, ok := join.consumed[i]; ok && b {
 				continue
 			}
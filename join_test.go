@@ -201,3 +201,24 @@ func TestJoinWithColumnName(t *testing.T) {
 		3, "Marine", "Prevost", "m.prevost@example.com", "Lille", time.Date(2013, time.February, 21, 0, 0, 0, 0, time.UTC), "A00106", 235.35,
 	)
 }
+
+func TestJoinReorderedExpr(t *testing.T) {
+	a := datatable.New("a")
+	assert.NoError(t, a.AddColumn("id", datatable.Int, datatable.Values(1, 2)))
+	assert.NoError(t, a.AddColumn("x", datatable.Int, datatable.Expr("`id` * 2")))
+	// the computed column is before its dependency
+	a, err := a.Reorder("x", "id")
+	assert.NoError(t, err)
+
+	b := datatable.New("b")
+	assert.NoError(t, b.AddColumn("id", datatable.Int, datatable.Values(1, 2)))
+	assert.NoError(t, b.AddColumn("y", datatable.Int, datatable.Values(3, 4)))
+
+	out, err := a.InnerJoin(b, datatable.On("[a].[id]", "[b].[id]"))
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"x", "id", "y",
+		2, 1, 3,
+		4, 2, 4,
+	)
+}
@@ -0,0 +1,564 @@
+package datatable
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/datasweet/expr"
+	"github.com/pkg/errors"
+	"github.com/xinzf/datatable/serie"
+)
+
+// LazyTable records operations on a datatable.
+// Nothing is computed until Collect: the plan is optimized,
+// the operations work on the row indexes and only the result is materialized.
+// After a Select, the next steps can only use the selected columns.
+type LazyTable struct {
+	dt    *DataTable
+	steps []lazyStep
+	err   error
+}
+
+type lazyStep interface {
+	String() string
+}
+
+type lazyFilter struct {
+	predicate  func(row Row) bool
+	formulae   string
+	node       expr.Node
+	refs       []string
+	aggregated bool // the expression aggregates the rows, ie avg(`col`)
+}
+
+func (s *lazyFilter) String() string {
+	if s.predicate != nil {
+		return "filter <func>"
+	}
+	return fmt.Sprintf("filter '%s'", s.formulae)
+}
+
+type lazySelect struct {
+	cols []string
+}
+
+func (s *lazySelect) String() string {
+	return "select " + strings.Join(s.cols, ", ")
+}
+
+type lazySort struct {
+	by  []SortBy
+	top int // top-K if > 0
+}
+
+func (s *lazySort) String() string {
+	keys := make([]string, len(s.by))
+	for i, by := range s.by {
+		keys[i] = by.Column
+		if by.Desc {
+			keys[i] += " desc"
+		}
+	}
+	if s.top > 0 {
+		return fmt.Sprintf("top %d by %s", s.top, strings.Join(keys, ", "))
+	}
+	return "sort by " + strings.Join(keys, ", ")
+}
+
+type lazyLimit struct {
+	n int
+}
+
+func (s *lazyLimit) String() string {
+	return fmt.Sprintf("limit %d", s.n)
+}
+
+type lazyJoin struct {
+	mode  joinType
+	right *DataTable
+	on    []JoinOn
+}
+
+func (s *lazyJoin) String() string {
+	var mode string
+	switch s.mode {
+	case innerJoin:
+		mode = "inner"
+	case leftJoin:
+		mode = "left"
+	case rightJoin:
+		mode = "right"
+	case outerJoin:
+		mode = "outer"
+	}
+	return fmt.Sprintf("%s join %s", mode, s.right.Name())
+}
+
+// Lazy starts a lazy query on the datatable
+// The operations are applied on the datatable as it is when Collect is called.
+func (t *DataTable) Lazy() *LazyTable {
+	l := &LazyTable{dt: t}
+	if t == nil {
+		l.err = ErrNilDatatable
+	}
+	return l
+}
+
+func (l *LazyTable) push(step lazyStep) *LazyTable {
+	l.steps = append(l.steps, step)
+	return l
+}
+
+// Filter keeps the rows matching the predicate
+func (l *LazyTable) Filter(predicate func(row Row) bool) *LazyTable {
+	if predicate == nil {
+		predicate = func(Row) bool { return false }
+	}
+	return l.push(&lazyFilter{predicate: predicate})
+}
+
+// FilterExpr keeps the rows matching the expression, see WhereExpr
+// The filters with an expression can be pushed down before the joins.
+func (l *LazyTable) FilterExpr(formulae string) *LazyTable {
+	formulae = strings.TrimSpace(formulae)
+	if len(formulae) == 0 {
+		return l
+	}
+	parsed, err := expr.Parse(formulae)
+	if err == nil {
+		err = checkFunctions(parsed)
+	}
+	if err != nil && l.err == nil {
+		err = errors.Wrapf(err, "filter expr '%s'", formulae)
+		l.err = errors.Wrap(err, ErrFormulaeSyntax.Error())
+	}
+	return l.push(&lazyFilter{
+		formulae:   formulae,
+		node:       parsed,
+		refs:       exprRefs(parsed),
+		aggregated: parsed != nil && exprAggregated(parsed),
+	})
+}
+
+// Select keeps the columns in this order
+func (l *LazyTable) Select(cols ...string) *LazyTable {
+	return l.push(&lazySelect{cols: cols})
+}
+
+// Sort sorts the rows
+func (l *LazyTable) Sort(by ...SortBy) *LazyTable {
+	if len(by) == 0 {
+		return l
+	}
+	return l.push(&lazySort{by: by})
+}
+
+// Limit keeps the {n} first rows
+func (l *LazyTable) Limit(n int) *LazyTable {
+	if n < 0 {
+		n = 0
+	}
+	return l.push(&lazyLimit{n: n})
+}
+
+// InnerJoin joins a datatable, see DataTable.InnerJoin
+func (l *LazyTable) InnerJoin(right *DataTable, on []JoinOn) *LazyTable {
+	return l.push(&lazyJoin{mode: innerJoin, right: right, on: on})
+}
+
+// LeftJoin joins a datatable, see DataTable.LeftJoin
+func (l *LazyTable) LeftJoin(right *DataTable, on []JoinOn) *LazyTable {
+	return l.push(&lazyJoin{mode: leftJoin, right: right, on: on})
+}
+
+// RightJoin joins a datatable, see DataTable.RightJoin
+func (l *LazyTable) RightJoin(right *DataTable, on []JoinOn) *LazyTable {
+	return l.push(&lazyJoin{mode: rightJoin, right: right, on: on})
+}
+
+// OuterJoin joins a datatable, see DataTable.OuterJoin
+func (l *LazyTable) OuterJoin(right *DataTable, on []JoinOn) *LazyTable {
+	return l.push(&lazyJoin{mode: outerJoin, right: right, on: on})
+}
+
+// Explain returns the optimized plan, one operation by line
+func (l *LazyTable) Explain() string {
+	steps := l.optimize()
+	lines := make([]string, len(steps))
+	for i, step := range steps {
+		lines[i] = step.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// optimize optimizes the plan
+// - filters are moved before sorts, and before selects and joins when the filter refs are known,
+// - a sort followed by a limit becomes a top-K.
+// Only filters and top-K are optimized: selects stay in place, the steps work on the row indexes
+// and a join only materializes the columns needed by the next steps.
+func (l *LazyTable) optimize() []lazyStep {
+	steps := make([]lazyStep, 0, len(l.steps))
+
+	for _, step := range l.steps {
+		switch s := step.(type) {
+		case *lazyFilter:
+			at := len(steps)
+			for at > 0 && canPushFilter(s, steps[at-1]) {
+				at--
+			}
+			steps = append(steps, nil)
+			copy(steps[at+1:], steps[at:])
+			steps[at] = s
+
+		case *lazyLimit:
+			// sort + limit => top-K, a select does not change the rows
+			at := len(steps) - 1
+			for at >= 0 {
+				if _, ok := steps[at].(*lazySelect); !ok {
+					break
+				}
+				at--
+			}
+			if at >= 0 {
+				if srt, ok := steps[at].(*lazySort); ok && s.n > 0 {
+					top := s.n
+					if srt.top > 0 && srt.top < top {
+						top = srt.top
+					}
+					steps[at] = &lazySort{by: srt.by, top: top}
+					continue
+				}
+			}
+			steps = append(steps, s)
+
+		default:
+			steps = append(steps, s)
+		}
+	}
+	return steps
+}
+
+// canPushFilter checks if the filter can be applied before the step
+func canPushFilter(filter *lazyFilter, step lazyStep) bool {
+	switch s := step.(type) {
+	case *lazySort:
+		return s.top == 0
+	case *lazySelect:
+		if filter.predicate != nil {
+			return false
+		}
+		cols := make(map[string]bool, len(s.cols))
+		for _, c := range s.cols {
+			cols[c] = true
+		}
+		for _, ref := range filter.refs {
+			if !cols[ref] {
+				return false
+			}
+		}
+		return true
+	case *lazyJoin:
+		// only on the left table, and the column names must not be changed by the join
+		if filter.predicate != nil || filter.aggregated || s.right == nil || s.mode == rightJoin || s.mode == outerJoin {
+			return false
+		}
+		for _, ref := range filter.refs {
+			if s.right.ColumnIndex(ref) >= 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// lazyState is the state of the execution
+type lazyState struct {
+	dt   *DataTable
+	rows []int    // selected rows in order
+	cols []string // selected columns, nil for all columns
+}
+
+// Collect executes the plan and returns the result
+func (l *LazyTable) Collect() (*DataTable, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	if err := l.dt.evaluateExpressions(); err != nil {
+		return nil, err
+	}
+
+	state := &lazyState{dt: l.dt, rows: allRows(l.dt.nrows)}
+	steps := l.optimize()
+
+	for i, step := range steps {
+		var err error
+		switch s := step.(type) {
+		case *lazyFilter:
+			err = state.filter(s)
+		case *lazySelect:
+			if err = state.dt.checkColumns(s.cols...); err == nil {
+				if state.cols != nil {
+					err = checkSelected(state.cols, s.cols)
+				}
+				state.cols = s.cols
+			}
+		case *lazySort:
+			err = state.sort(s)
+		case *lazyLimit:
+			if s.n < len(state.rows) {
+				state.rows = state.rows[:s.n]
+			}
+		case *lazyJoin:
+			err = state.join(s, neededColumns(steps[i+1:]))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return state.materialize(nil)
+}
+
+func allRows(n int) []int {
+	rows := make([]int, n)
+	for i := range rows {
+		rows[i] = i
+	}
+	return rows
+}
+
+// checkSelected checks if the columns are selected
+func checkSelected(selected, cols []string) error {
+	set := make(map[string]bool, len(selected))
+	for _, c := range selected {
+		set[c] = true
+	}
+	for _, c := range cols {
+		if !set[c] {
+			err := errors.Errorf("column '%s' not selected", c)
+			return errors.Wrap(err, ErrColumnNotFound.Error())
+		}
+	}
+	return nil
+}
+
+// neededColumns returns the columns needed by the steps
+// returns nil if all columns are needed
+func neededColumns(steps []lazyStep) map[string]bool {
+	needed := make(map[string]bool)
+	for _, step := range steps {
+		switch s := step.(type) {
+		case *lazyFilter:
+			if s.predicate != nil {
+				return nil
+			}
+			for _, ref := range s.refs {
+				needed[ref] = true
+			}
+		case *lazySort:
+			for _, by := range s.by {
+				needed[by.Column] = true
+			}
+		case *lazySelect:
+			for _, c := range s.cols {
+				needed[c] = true
+			}
+			return needed
+		case *lazyJoin:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *lazyState) filter(f *lazyFilter) error {
+	if f.predicate == nil {
+		if s.cols != nil {
+			if err := checkSelected(s.cols, f.refs); err != nil {
+				return errors.Wrapf(err, "filter expr '%s'", f.formulae)
+			}
+		}
+		if err := s.dt.checkColumns(f.refs...); err != nil {
+			return errors.Wrapf(err, "filter expr '%s'", f.formulae)
+		}
+		if err := s.dt.checkFunctionColumns(f.node); err != nil {
+			return errors.Wrapf(err, "filter expr '%s'", f.formulae)
+		}
+		// the expression only sees the current rows
+		values, err := s.dt.evalNodeAt(f.node, s.rows, nil)
+		if err != nil {
+			return errors.Wrapf(err, "filter expr '%s'", f.formulae)
+		}
+		keep, err := whereRows(values)
+		if err != nil {
+			return errors.Wrapf(err, "filter expr '%s'", f.formulae)
+		}
+		rows := make([]int, 0, len(keep))
+		for _, k := range keep {
+			rows = append(rows, s.rows[k])
+		}
+		s.rows = rows
+		return nil
+	}
+
+	cols := s.dt.cols
+	if s.cols != nil {
+		cols = make([]*column, 0, len(s.cols))
+		for _, name := range s.cols {
+			cols = append(cols, s.dt.cols[s.dt.ColumnIndex(name)])
+		}
+	}
+
+	rows := s.rows[:0:0]
+	for _, i := range s.rows {
+		r := make(Row, len(cols))
+		for _, col := range cols {
+			r[col.name] = col.serie.Get(i)
+		}
+		if f.predicate(r) {
+			rows = append(rows, i)
+		}
+	}
+	s.rows = rows
+	return nil
+}
+
+// sort sorts the rows with a stable sort, or a top-K heap
+func (s *lazyState) sort(srt *lazySort) error {
+	series := make([]serie.Serie, len(srt.by))
+	for i, by := range srt.by {
+		if s.cols != nil {
+			if err := checkSelected(s.cols, []string{by.Column}); err != nil {
+				return errors.Wrap(err, "sort")
+			}
+		}
+		pos := s.dt.ColumnIndex(by.Column)
+		if pos < 0 {
+			err := errors.Errorf("column '%s' not found", by.Column)
+			return errors.Wrap(err, ErrColumnNotFound.Error())
+		}
+		series[i] = s.dt.cols[pos].serie
+	}
+
+	// less compares the positions in rows, the position breaks the ties
+	rows := s.rows
+	less := func(i, j int) bool {
+		for k, by := range srt.by {
+			switch series[k].Compare(rows[i], rows[j]) {
+			case serie.Gt:
+				return by.Desc
+			case serie.Lt:
+				return !by.Desc
+			}
+		}
+		return i < j
+	}
+
+	var positions []int
+	if srt.top > 0 && srt.top < len(rows) {
+		h := &topHeap{less: less}
+		for i := range rows {
+			if h.Len() < srt.top {
+				heap.Push(h, i)
+			} else if less(i, h.positions[0]) {
+				h.positions[0] = i
+				heap.Fix(h, 0)
+			}
+		}
+		positions = h.positions
+	} else {
+		positions = allRows(len(rows))
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return less(positions[i], positions[j])
+	})
+
+	sorted := make([]int, len(positions))
+	for i, pos := range positions {
+		sorted[i] = rows[pos]
+	}
+	s.rows = sorted
+	return nil
+}
+
+// join materializes the current state with the needed columns and joins the right table
+func (s *lazyState) join(j *lazyJoin, needed map[string]bool) error {
+	left, err := s.materialize(func(name string) bool {
+		if needed == nil || needed[name] {
+			return true
+		}
+		// keys and common columns
+		if j.right != nil && j.right.ColumnIndex(name) >= 0 {
+			return true
+		}
+		for _, on := range j.on {
+			if on.Field == name {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+
+	out, err := newJoinImpl(j.mode, []*DataTable{left, j.right}, j.on).Compute()
+	if err != nil {
+		return err
+	}
+	// the next steps read the series of the computed columns
+	if err := out.evaluateExpressions(); err != nil {
+		return err
+	}
+	s.dt = out
+	s.rows = allRows(out.nrows)
+	s.cols = nil
+	return nil
+}
+
+// materialize creates the datatable with the selected rows and columns
+// keep filters the columns, if not nil.
+func (s *lazyState) materialize(keep func(name string) bool) (*DataTable, error) {
+	cols := s.cols
+	if cols == nil {
+		cols = make([]string, 0, len(s.dt.cols))
+		for _, col := range s.dt.cols {
+			cols = append(cols, col.name)
+		}
+	}
+	if keep != nil {
+		kept := cols[:0:0]
+		for _, name := range cols {
+			if keep(name) {
+				kept = append(kept, name)
+			}
+		}
+		cols = kept
+	}
+
+	out, err := s.dt.project(cols...)
+	if err != nil {
+		return nil, err
+	}
+	return out.pick(s.rows...), nil
+}
+
+// topHeap keeps the {k} first positions: the root is the last one
+type topHeap struct {
+	positions []int
+	less      func(i, j int) bool
+}
+
+func (h *topHeap) Len() int           { return len(h.positions) }
+func (h *topHeap) Less(i, j int) bool { return h.less(h.positions[j], h.positions[i]) }
+func (h *topHeap) Swap(i, j int)      { h.positions[i], h.positions[j] = h.positions[j], h.positions[i] }
+func (h *topHeap) Push(x interface{}) { h.positions = append(h.positions, x.(int)) }
+func (h *topHeap) Pop() interface{} {
+	n := len(h.positions)
+	x := h.positions[n-1]
+	h.positions = h.positions[:n-1]
+	return x
+}
@@ -0,0 +1,131 @@
+package datatable_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func TestLazy(t *testing.T) {
+	sales := sampleForGroups()
+
+	lazy := sales.Lazy().
+		Sort(datatable.SortBy{Column: "amount", Desc: true}).
+		Select("city", "amount").
+		FilterExpr("`amount` >= 10").
+		Limit(3)
+	assert.Equal(t, "filter '`amount` >= 10'\ntop 3 by amount desc\nselect city, amount", lazy.Explain())
+
+	dt, err := lazy.Collect()
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"city", "amount",
+		"marseille", 30,
+		"amiens", 20,
+		"nice", 15,
+	)
+
+	// ties keep the initial order
+	dt, err = sales.Lazy().
+		Filter(func(row datatable.Row) bool { return row.Get("region") != "east" }).
+		Sort(datatable.SortBy{Column: "region"}).
+		Limit(4).
+		Collect()
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"region", "city", "amount",
+		"north", "lille", 10,
+		"north", "amiens", 20,
+		"south", "nice", 5,
+		"south", "nice", 15,
+	)
+
+	// a filter is never moved before a limit
+	lazy = sales.Lazy().Limit(2).FilterExpr("`amount` > 10")
+	assert.Equal(t, "limit 2\nfilter '`amount` > 10'", lazy.Explain())
+	dt, err = lazy.Collect()
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"region", "city", "amount",
+		"north", "amiens", 20,
+	)
+
+	// aggregations only see the filtered rows
+	dt, err = sales.Lazy().
+		FilterExpr("`region` == \"south\"").
+		FilterExpr("`amount` > avg(`amount`)").
+		Collect()
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"region", "city", "amount",
+		"south", "marseille", 30,
+	)
+
+	// the source table is not modified
+	assert.Equal(t, 6, sales.NumRows())
+}
+
+func TestLazyJoin(t *testing.T) {
+	customers, orders := sampleForJoin()
+
+	lazy := customers.Lazy().
+		LeftJoin(orders, datatable.On("[Customers].[id]", "[Orders].[user_id]")).
+		FilterExpr("`ville` != \"Paris\"").
+		FilterExpr("`prix_total` > 140").
+		Sort(datatable.SortBy{Column: "prix_total"}).
+		Select("prenom", "num_facture", "prix_total")
+	assert.Equal(t, "filter '`ville` != \"Paris\"'\nleft join Orders\nfilter '`prix_total` > 140'\nsort by prix_total\nselect prenom, num_facture, prix_total", lazy.Explain())
+
+	dt, err := lazy.Collect()
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"prenom", "num_facture", "prix_total",
+		"Esmée", "A00105", 149.45,
+		"Marine", "A00106", 235.35,
+	)
+}
+
+func TestLazyJoinComputed(t *testing.T) {
+	a := datatable.New("A")
+	a.AddColumn("id", datatable.Int, datatable.Values(1, 2, 3))
+	a.AddColumn("v", datatable.Int, datatable.Values(1, 3, 2))
+	a.AddColumn("dbl", datatable.Int, datatable.Expr("`v` * 2"))
+
+	b := datatable.New("B")
+	b.AddColumn("uid", datatable.Int, datatable.Values(1, 2, 3))
+	b.AddColumn("label", datatable.String, datatable.Values("x", "y", "z"))
+
+	dt, err := a.Lazy().
+		InnerJoin(b, datatable.On("[A].[id]", "[B].[uid]")).
+		Filter(func(row datatable.Row) bool { return row.Get("dbl").(int) > 2 }).
+		Sort(datatable.SortBy{Column: "dbl", Desc: true}).
+		Select("label", "dbl").
+		Collect()
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"label", "dbl",
+		"y", 6,
+		"z", 4,
+	)
+}
+
+func TestLazyErrors(t *testing.T) {
+	sales := sampleForGroups()
+
+	for _, lazy := range []*datatable.LazyTable{
+		sales.Lazy().FilterExpr("`amount` >"),
+		sales.Lazy().FilterExpr("`unknown` > 1"),
+		sales.Lazy().Select("unknown"),
+		sales.Lazy().Select("city").Select("amount"),
+		sales.Lazy().Sort(datatable.SortBy{Column: "unknown"}),
+		sales.Lazy().Select("city").FilterExpr("`amount` > 1"),
+		sales.Lazy().Select("city").Sort(datatable.SortBy{Column: "amount"}),
+		sales.Lazy().Select("city").Sort(datatable.SortBy{Column: "amount"}).Limit(2),
+		sales.Lazy().FilterExpr("`city`"),
+	} {
+		dt, err := lazy.Collect()
+		assert.Error(t, err)
+		assert.Nil(t, dt)
+	}
+}
@@ -0,0 +1,81 @@
+package datatable
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Melt reshapes the datatable from wide to long (unpivot).
+// Each row is split into one row per {valueCols} with the {idCols}, the name of the
+// value column in {varName} and its value in {valueName}.
+// If {valueCols} is empty, all visible columns not in {idCols} are melted.
+// The type of the value column is the common type of the melted columns (or raw).
+func (dt *DataTable) Melt(idCols []string, valueCols []string, varName, valueName string) (*DataTable, error) {
+	if len(varName) == 0 {
+		varName = "variable"
+	}
+	if len(valueName) == 0 {
+		valueName = "value"
+	}
+
+	if err := dt.checkColumns(idCols...); err != nil {
+		return nil, err
+	}
+	if err := dt.checkColumns(valueCols...); err != nil {
+		return nil, err
+	}
+
+	if len(valueCols) == 0 {
+		mid := make(map[string]bool, len(idCols))
+		for _, name := range idCols {
+			mid[name] = true
+		}
+		for _, col := range dt.cols {
+			if col.IsVisible() && !mid[col.name] {
+				valueCols = append(valueCols, col.name)
+			}
+		}
+	}
+
+	if err := dt.evaluateExpressions(); err != nil {
+		return nil, err
+	}
+
+	types := make([]ColumnType, 0, len(valueCols))
+	for _, name := range valueCols {
+		types = append(types, dt.Column(name).Type())
+	}
+
+	// create columns
+	out := New(dt.name)
+	for _, name := range idCols {
+		if err := out.addColumn(dt.cols[dt.ColumnIndex(name)].plainCopy()); err != nil {
+			err = errors.Wrapf(err, "can't add column '%s'", name)
+			return nil, errors.Wrap(err, ErrCantAddColumn.Error())
+		}
+	}
+	if err := out.AddColumn(varName, String); err != nil {
+		err = errors.Wrapf(err, "can't add column '%s'", varName)
+		return nil, errors.Wrap(err, ErrCantAddColumn.Error())
+	}
+	if err := out.AddColumn(valueName, commonColumnType(types...)); err != nil {
+		err = errors.Wrapf(err, "can't add column '%s'", valueName)
+		return nil, errors.Wrap(err, ErrCantAddColumn.Error())
+	}
+
+	// fill values
+	nid := len(idCols)
+	for _, name := range valueCols {
+		for i, id := range idCols {
+			if err := out.cols[i].serie.Concat(dt.Column(id).Serie()); err != nil {
+				return nil, err
+			}
+		}
+		for i := 0; i < dt.nrows; i++ {
+			out.cols[nid].serie.Append(name)
+		}
+		out.cols[nid+1].serie.Append(dt.Column(name).Serie().All()...)
+		out.nrows += dt.nrows
+	}
+
+	return out, nil
+}
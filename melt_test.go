@@ -0,0 +1,46 @@
+package datatable_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func TestMelt(t *testing.T) {
+	dt := datatable.New("sales")
+	dt.AddColumn("shop", datatable.String, datatable.Values("paris", "lyon"), datatable.ColumnLabel("Shop"))
+	dt.AddColumn("jan", datatable.Int, datatable.Values(10, 20))
+	dt.AddColumn("feb", datatable.Int64, datatable.Values(11, nil))
+	dt.AddColumn("mar", datatable.Int, datatable.Expr("`jan` * 2"))
+
+	out, err := dt.Melt([]string{"shop"}, nil, "month", "")
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"shop", "month", "value",
+		"paris", "jan", int64(10),
+		"lyon", "jan", int64(20),
+		"paris", "feb", int64(11),
+		"lyon", "feb", nil,
+		"paris", "mar", int64(20),
+		"lyon", "mar", int64(40),
+	)
+	assert.Equal(t, "Shop", out.Column("shop").Label())
+	assert.Equal(t, datatable.Int64, out.Column("value").Type())
+
+	// mixed types
+	dt.AddColumn("comment", datatable.String, datatable.Values("ok", "ko"))
+	out, err = dt.Melt([]string{"shop"}, []string{"jan", "comment"}, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, datatable.Raw, out.Column("value").Type())
+	checkTable(t, out,
+		"shop", "variable", "value",
+		"paris", "jan", 10,
+		"lyon", "jan", 20,
+		"paris", "comment", "ok",
+		"lyon", "comment", "ko",
+	)
+
+	_, err = dt.Melt([]string{"unknown"}, nil, "", "")
+	assert.Error(t, err)
+}
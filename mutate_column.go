@@ -3,10 +3,35 @@ package datatable
 import (
 	"strings"
 
-	"github.com/datasweet/expr"
 	"github.com/pkg/errors"
 )
 
+// checkExpr parses the formula of a new column and checks its references and function calls
+// Also checks that no expression of the datatable calls a function with the name of the column.
+func (t *DataTable) checkExpr(col *column) error {
+	if len(col.formulae) > 0 {
+		if err := col.parse(); err != nil {
+			return errors.Wrapf(err, ErrFormulaeSyntax.Error())
+		}
+		if err := t.checkExprCycle(col); err != nil {
+			return err
+		}
+		if err := t.checkExprRefs(col); err != nil {
+			return err
+		}
+		if err := t.checkFunctionColumns(col.expr); err != nil {
+			return err
+		}
+		for _, fn := range exprFunctions(col.expr) {
+			if fn == col.name {
+				err := errors.Errorf("column '%s' hides the function called by its expression", col.name)
+				return errors.Wrap(err, ErrFunctionColumnClash.Error())
+			}
+		}
+	}
+	return t.checkColumnFunctions(col.name)
+}
+
 func (t *DataTable) addColumn(col *column) error {
 	if col == nil {
 		return ErrNilColumn
@@ -27,12 +52,10 @@ func (t *DataTable) addColumn(col *column) error {
 	}
 
 	// Check formula
-	if len(col.formulae) > 0 {
-		parsed, err := expr.Parse(col.formulae)
-		if err != nil {
-			return errors.Wrapf(err, ErrFormulaeSyntax.Error())
-		}
-		col.expr = parsed
+	if err := t.checkExpr(col); err != nil {
+		return err
+	}
+	if col.expr != nil {
 		t.hasExpr = true
 	}
 
@@ -41,6 +64,7 @@ func (t *DataTable) addColumn(col *column) error {
 		return ErrNilSerie
 	}
 	ln := col.serie.Len()
+	nrows := t.nrows
 
 	if ln < t.nrows {
 		col.serie.Grow(t.nrows - ln)
@@ -53,7 +77,8 @@ func (t *DataTable) addColumn(col *column) error {
 	}
 
 	t.cols = append(t.cols, col)
-	t.dirty = true
+	t.markRows(nrows, t.nrows)
+	t.markColumn(col.name)
 	return nil
 }
 
@@ -101,12 +126,10 @@ func (t *DataTable) unshiftColumn(col *column) error {
 	}
 
 	// Check formula
-	if len(col.formulae) > 0 {
-		parsed, err := expr.Parse(col.formulae)
-		if err != nil {
-			return errors.Wrapf(err, ErrFormulaeSyntax.Error())
-		}
-		col.expr = parsed
+	if err := t.checkExpr(col); err != nil {
+		return err
+	}
+	if col.expr != nil {
 		t.hasExpr = true
 	}
 
@@ -115,6 +138,7 @@ func (t *DataTable) unshiftColumn(col *column) error {
 		return ErrNilSerie
 	}
 	ln := col.serie.Len()
+	nrows := t.nrows
 
 	if ln < t.nrows {
 		col.serie.Grow(t.nrows - ln)
@@ -128,7 +152,8 @@ func (t *DataTable) unshiftColumn(col *column) error {
 
 	t.cols = append([]*column{col}, t.cols...)
 	//t.cols = append(t.cols, col)
-	t.dirty = true
+	t.markRows(nrows, t.nrows)
+	t.markColumn(col.name)
 	return nil
 }
 
@@ -191,8 +216,15 @@ func (t *DataTable) RenameColumn(old, name string) error {
 		err := errors.Errorf("column '%s' already exists", name)
 		return errors.Wrap(err, ErrColumnAlreadyExists.Error())
 	}
+	if err := t.checkColumnFunctions(name); err != nil {
+		return err
+	}
 	if col := t.Column(old); col != nil {
 		col.(*column).name = name
+		if t.dirtyCols[old] {
+			delete(t.dirtyCols, old)
+			t.markColumn(name)
+		}
 		return nil
 	}
 	err := errors.Errorf("column '%s' does not exist", name)
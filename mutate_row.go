@@ -12,6 +12,7 @@ func (t *DataTable) NewRow() Row {
 
 // Append rows to the table
 func (t *DataTable) Append(row ...Row) {
+	from := t.nrows
 	for _, r := range row {
 		if r == nil {
 			continue
@@ -27,7 +28,7 @@ func (t *DataTable) Append(row ...Row) {
 		}
 		t.nrows++
 	}
-	t.dirty = true
+	t.markRows(from, t.nrows)
 }
 
 // AppendRow creates a new row and append cells to this row
@@ -46,7 +47,7 @@ func (t *DataTable) AppendRow(v ...interface{}) error {
 	}
 
 	t.nrows++
-	t.dirty = true
+	t.markRows(t.nrows-1, t.nrows)
 
 	return nil
 }
@@ -56,6 +57,16 @@ func (t *DataTable) SwapRow(i, j int) {
 	for _, col := range t.cols {
 		col.serie.Swap(i, j)
 	}
+	// the changed rows follow their values
+	if t.dirtyRows[i] != t.dirtyRows[j] {
+		if t.dirtyRows[i] {
+			delete(t.dirtyRows, i)
+			t.dirtyRows[j] = true
+		} else {
+			delete(t.dirtyRows, j)
+			t.dirtyRows[i] = true
+		}
+	}
 }
 
 // Grow the table by size
@@ -89,5 +100,131 @@ func (t *DataTable) Update(at int, row Row) error {
 		}
 	}
 
+	t.markRows(at, at+1)
+	return nil
+}
+
+// checkRowIndex checks if the row index is in range
+func (t *DataTable) checkRowIndex(at int) error {
+	if at < 0 || at >= t.nrows {
+		err := errors.Errorf("row [%d]: index out of range with length %d", at, t.nrows)
+		return errors.Wrap(err, ErrOutOfRange.Error())
+	}
+	return nil
+}
+
+// DeleteRow deletes the row at index
+// On error, the table is not modified.
+func (t *DataTable) DeleteRow(at int) error {
+	if err := t.checkRowIndex(at); err != nil {
+		return err
+	}
+	// checks all columns before deleting, the columns must keep the same length
+	for _, col := range t.cols {
+		if l := col.serie.Len(); l != t.nrows {
+			err := errors.Errorf("col %s: size mismatch %d vs %d", col.name, l, t.nrows)
+			return errors.Wrap(err, ErrLengthMismatch.Error())
+		}
+	}
+	for _, col := range t.cols {
+		if err := col.serie.Delete(at); err != nil {
+			return errors.Wrapf(err, "col %s", col.name)
+		}
+	}
+	t.nrows--
+	t.dirty = true
+	return nil
+}
+
+// DeleteRows deletes the rows matching the predicate
+// returns the number of deleted rows
+func (t *DataTable) DeleteRows(predicate func(row Row) bool) (int, error) {
+	if predicate == nil {
+		return 0, nil
+	}
+
+	if err := t.evaluateExpressions(); err != nil {
+		return 0, err
+	}
+
+	keep := make([]int, 0, t.nrows)
+	for i := 0; i < t.nrows; i++ {
+		r := make(Row, len(t.cols))
+		for _, col := range t.cols {
+			r[col.name] = col.serie.Get(i)
+		}
+		if !predicate(r) {
+			keep = append(keep, i)
+		}
+	}
+
+	deleted := t.nrows - len(keep)
+	if deleted == 0 {
+		return 0, nil
+	}
+
+	for _, col := range t.cols {
+		col.serie = col.serie.Pick(keep...)
+	}
+	t.nrows = len(keep)
+	t.dirty = true
+	return deleted, nil
+}
+
+// InsertRow inserts a row at index
+// If at is the number of rows, the row is appended. On error, the table is not modified.
+func (t *DataTable) InsertRow(at int, row Row) error {
+	// at the end, the row is appended
+	end := at == t.nrows
+	if !end {
+		if err := t.checkRowIndex(at); err != nil {
+			return err
+		}
+	}
+
+	for i, col := range t.cols {
+		var cell interface{}
+		if !col.IsComputed() {
+			cell = row[col.name]
+		}
+		n := col.serie.Len()
+		var err error
+		if end {
+			col.serie.Append(cell)
+		} else {
+			err = col.serie.Insert(at, cell)
+		}
+		if err == nil && col.serie.Len() != n+1 {
+			// ie a slice is inserted as many values
+			err = errors.Errorf("expected 1 value, got %d", col.serie.Len()-n)
+		}
+		if err != nil {
+			// roll back the inserted values, the table is not modified
+			for k := col.serie.Len(); k > n; k-- {
+				col.serie.Delete(at)
+			}
+			for _, prev := range t.cols[:i] {
+				prev.serie.Delete(at)
+			}
+			err := errors.Wrapf(err, "col %s", col.name)
+			return errors.Wrap(err, ErrInsertRow.Error())
+		}
+	}
+
+	t.nrows++
+	if end {
+		t.markRows(at, t.nrows)
+	} else {
+		t.dirty = true
+	}
 	return nil
 }
+
+// Truncate deletes all rows
+func (t *DataTable) Truncate() {
+	for _, col := range t.cols {
+		col.serie.Clear()
+	}
+	t.nrows = 0
+	t.dirty = true
+}
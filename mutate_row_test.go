@@ -3,6 +3,7 @@ package datatable_test
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/xinzf/datatable"
 )
 
@@ -29,3 +30,69 @@ func TestSwapRow(t *testing.T) {
 		"Malzahar", "MALZAHAR", 10, 6, 62.5,
 	)
 }
+
+func TestDeleteRow(t *testing.T) {
+	tb := New(t)
+
+	assert.NoError(t, tb.DeleteRow(1))
+	assert.Error(t, tb.DeleteRow(2))
+	assert.Error(t, tb.DeleteRow(-1))
+
+	checkTable(t, tb,
+		"champ", "champion", "win", "loose", "winRate", "sum", "ok",
+		"Malzahar", "MALZAHAR", 10, 6, "62.5 %", 676.0, true,
+		"Teemo", "TEEMO", 666, 666, "50 %", 676.0, true,
+	)
+}
+
+func TestDeleteRows(t *testing.T) {
+	tb := New(t)
+
+	n, err := tb.DeleteRows(func(row datatable.Row) bool {
+		return row.Get("champion") != "XERATH"
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	checkTable(t, tb,
+		"champ", "champion", "win", "loose", "winRate", "sum", "ok",
+		"Xerath", "XERATH", 20, 5, "80 %", 20.0, true,
+	)
+}
+
+func TestInsertRow(t *testing.T) {
+	tb := New(t)
+
+	assert.NoError(t, tb.InsertRow(1, datatable.Row{"champ": "Ahri", "win": 4, "loose": 4, "champion": "ignored"}))
+	assert.NoError(t, tb.InsertRow(4, datatable.Row{"champ": "Lux", "win": 6, "loose": 2}))
+	assert.Error(t, tb.InsertRow(6, datatable.Row{"champ": "Zed"}))
+
+	// the columns before "loose" are rolled back
+	assert.Error(t, tb.InsertRow(2, datatable.Row{"champ": "Zed", "win": 1, "loose": []int{1, 2}}))
+	assert.Error(t, tb.InsertRow(2, datatable.Row{"champ": "Zed", "win": 1, "loose": []int{}}))
+	// also at the end
+	assert.Error(t, tb.InsertRow(5, datatable.Row{"champ": "Zed", "win": 1, "loose": []int{1, 2}}))
+	assert.Equal(t, 5, tb.NumRows())
+
+	checkTable(t, tb,
+		"champ", "champion", "win", "loose", "winRate", "sum", "ok",
+		"Malzahar", "MALZAHAR", 10, 6, "62.5 %", 706.0, true,
+		"Ahri", "AHRI", 4, 4, "50 %", 706.0, true,
+		"Xerath", "XERATH", 20, 5, "80 %", 706.0, true,
+		"Teemo", "TEEMO", 666, 666, "50 %", 706.0, true,
+		"Lux", "LUX", 6, 2, "75 %", 706.0, true,
+	)
+}
+
+func TestTruncate(t *testing.T) {
+	tb := New(t)
+	tb.Truncate()
+	assert.Equal(t, 0, tb.NumRows())
+	assert.Equal(t, 7, tb.NumCols())
+
+	tb.Append(datatable.Row{"champ": "Ahri", "win": 4, "loose": 4})
+	checkTable(t, tb,
+		"champ", "champion", "win", "loose", "winRate", "sum", "ok",
+		"Ahri", "AHRI", 4, 4, "50 %", 4.0, true,
+	)
+}
@@ -0,0 +1,161 @@
+package datatable
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// PivotOptions describes options to be apply on a pivot
+type PivotOptions struct {
+	Columns []interface{} // explicit order of the pivoted columns, sorted distinct values if empty
+	Fill    interface{}   // value of the empty cells
+}
+
+// PivotOption sets pivot options
+type PivotOption func(opts *PivotOptions)
+
+// PivotColumns sets the values of the pivoted column to be used as output columns, in this order.
+// Values not in the list are ignored.
+func PivotColumns(v ...interface{}) PivotOption {
+	return func(opts *PivotOptions) {
+		opts.Columns = v
+	}
+}
+
+// PivotFill sets the value of the empty cells
+func PivotFill(v interface{}) PivotOption {
+	return func(opts *PivotOptions) {
+		opts.Fill = v
+	}
+}
+
+// Pivot reshapes the datatable from long to wide.
+// Rows are grouped by the {index} columns and one column is created for each
+// distinct value of {columns}, containing the aggregation of {values}.
+// Rows with a nil {columns} value are ignored. A column is named after its value,
+// with a suffix if the name is already used, ie 1 and "1" give "1" and "1_1".
+func (dt *DataTable) Pivot(index []string, columns string, values string, agg AggregationType, opt ...PivotOption) (*DataTable, error) {
+	out, _, err := dt.pivot(index, columns, values, agg, opt...)
+	return out, err
+}
+
+// pivot pivots the datatable, and returns the value of each pivoted column
+// The values are matched on their hash, which flags nil values.
+func (dt *DataTable) pivot(index []string, columns string, values string, agg AggregationType, opt ...PivotOption) (*DataTable, []interface{}, error) {
+	var options PivotOptions
+	for _, o := range opt {
+		o(&options)
+	}
+
+	if len(index) == 0 {
+		return nil, nil, ErrNoGroupBy
+	}
+	keys := make([]string, 0, len(index)+1)
+	keys = append(keys, index...)
+	keys = append(keys, columns)
+	if err := dt.checkColumns(append(keys, values)...); err != nil {
+		return nil, nil, err
+	}
+
+	// group by index, columns
+	by := make([]GroupBy, 0, len(keys))
+	for _, name := range keys {
+		name := name
+		by = append(by, GroupBy{
+			Name: name,
+			Keyer: func(row Row) (interface{}, bool) {
+				return row.Get(name), true
+			},
+		})
+	}
+	groups, err := dt.GroupBy(by...)
+	if err != nil {
+		return nil, nil, err
+	}
+	aggregated, err := groups.Aggregate(AggregateBy{Type: agg, Field: values})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// pivoted values
+	pivoted := options.Columns
+	if len(pivoted) == 0 {
+		distinct := dt.Column(columns).Serie().NonNils().Distinct()
+		distinct.SortAsc()
+		pivoted = distinct.All()
+	}
+
+	// create columns
+	out := New(dt.name)
+	used := make(map[string]bool, len(index)+len(pivoted))
+	for _, name := range index {
+		if err := out.addColumn(dt.cols[dt.ColumnIndex(name)].plainCopy()); err != nil {
+			err = errors.Wrapf(err, "can't add column '%s'", name)
+			return nil, nil, errors.Wrap(err, ErrCantAddColumn.Error())
+		}
+		used[name] = true
+	}
+	typ := agg.columnType(dt.Column(values).Type())
+	mpos := make(map[uint64]int, len(pivoted))
+	colValues := make([]interface{}, 0, len(pivoted))
+	for _, v := range pivoted {
+		hash := hashKeys([]interface{}{v})
+		if _, ok := mpos[hash]; ok {
+			continue
+		}
+		label := fmt.Sprint(v)
+		name := label
+		for k := 1; used[name]; k++ {
+			name = fmt.Sprintf("%s_%d", label, k)
+		}
+		if err := out.AddColumn(name, typ); err != nil {
+			err = errors.Wrapf(err, "can't add column '%s'", name)
+			return nil, nil, errors.Wrap(err, ErrCantAddColumn.Error())
+		}
+		used[name] = true
+		mpos[hash] = len(index) + len(colValues)
+		colValues = append(colValues, v)
+	}
+
+	// fill cells
+	var rows [][]interface{}
+	rindex := make(map[uint64]int)
+	nkeys := len(index)
+
+	for i := 0; i < aggregated.nrows; i++ {
+		keys := make([]interface{}, 0, nkeys)
+		for k := 0; k < nkeys; k++ {
+			keys = append(keys, aggregated.cols[k].serie.Get(i))
+		}
+
+		hash := hashKeys(keys)
+		at, ok := rindex[hash]
+		if !ok {
+			row := make([]interface{}, len(out.cols))
+			copy(row, keys)
+			for j := nkeys; j < len(row); j++ {
+				row[j] = options.Fill
+			}
+			at = len(rows)
+			rindex[hash] = at
+			rows = append(rows, row)
+		}
+
+		pivot := aggregated.cols[nkeys].serie.Get(i)
+		if pivot == nil {
+			continue
+		}
+		if pos, ok := mpos[hashKeys([]interface{}{pivot})]; ok {
+			rows[at][pos] = aggregated.cols[nkeys+1].serie.Get(i)
+		}
+	}
+
+	for _, row := range rows {
+		if err := out.AppendRow(row...); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return out, colValues, nil
+}
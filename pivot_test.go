@@ -0,0 +1,71 @@
+package datatable_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func sampleForPivot() *datatable.DataTable {
+	dt := datatable.New("calls")
+	dt.AddColumn("month", datatable.String, datatable.Values("2014-11", "2014-11", "2014-11", "2014-12", "2014-12", "2015-01"))
+	dt.AddColumn("network", datatable.String, datatable.Values("Vodafone", "Meteor", "Vodafone", "Three", "Meteor", "Vodafone"))
+	dt.AddColumn("duration", datatable.Int, datatable.Values(10, 20, 30, 5, 15, 8))
+	return dt
+}
+
+func TestPivot(t *testing.T) {
+	dt := sampleForPivot()
+
+	out, err := dt.Pivot([]string{"month"}, "network", "duration", datatable.Sum)
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"month", "Meteor", "Three", "Vodafone",
+		"2014-11", 20.0, nil, 40.0,
+		"2014-12", 15.0, 5.0, nil,
+		"2015-01", nil, nil, 8.0,
+	)
+
+	out, err = dt.Pivot([]string{"month"}, "network", "duration", datatable.Count,
+		datatable.PivotColumns("Vodafone", "Three"),
+		datatable.PivotFill(0),
+	)
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"month", "Vodafone", "Three",
+		"2014-11", int64(2), int64(0),
+		"2014-12", int64(0), int64(1),
+		"2015-01", int64(1), int64(0),
+	)
+
+	_, err = dt.Pivot([]string{"month"}, "unknown", "duration", datatable.Sum)
+	assert.Error(t, err)
+	_, err = dt.Pivot(nil, "network", "duration", datatable.Sum)
+	assert.Error(t, err)
+}
+
+func TestPivotSameNames(t *testing.T) {
+	dt := datatable.New("mixed")
+	dt.AddColumn("k", datatable.String, datatable.Values("a", "a", "b"))
+	dt.AddColumn("p", datatable.Raw, datatable.Values(1, "1", "<nil>"))
+	dt.AddColumn("v", datatable.Int, datatable.Values(10, 20, 30))
+
+	// 1 and "1" are 2 columns
+	out, err := dt.Pivot([]string{"k"}, "p", "v", datatable.Sum, datatable.PivotColumns(1, "1"))
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"k", "1", "1_1",
+		"a", 10.0, 20.0,
+		"b", nil, nil,
+	)
+
+	// a nil column is not the "<nil>" column
+	out, err = dt.Pivot([]string{"k"}, "p", "v", datatable.Sum, datatable.PivotColumns(nil, "<nil>"))
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"k", "<nil>", "<nil>_1",
+		"a", nil, nil,
+		"b", nil, 30.0,
+	)
+}
@@ -0,0 +1,1010 @@
+package datatable
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/datasweet/cast"
+	"github.com/datasweet/expr"
+	"github.com/pkg/errors"
+	"github.com/xinzf/datatable/query"
+	"github.com/xinzf/datatable/serie"
+)
+
+// Query executes a SQL SELECT query on the tables.
+// The tables are referenced by their key in the FROM and JOIN clauses.
+// Supported: SELECT [DISTINCT] with expressions and aliases, INNER/LEFT/RIGHT/OUTER JOIN ... ON,
+// WHERE, GROUP BY with the aggregations (count, sum, avg, min, max, median, stddev, variance,
+// group_concat, any_value), HAVING, ORDER BY, LIMIT and OFFSET.
+// The expressions can use the builtins of the expressions and the registered functions.
+// As in SQL, a builtin or a || with a NULL argument is NULL, a registered function follows RegisterFunction.
+// A NULL join key matches no row, and an aggregation of no values is NULL, except count.
+// With DISTINCT, the ORDER BY expressions must appear in the select list.
+func Query(sql string, tables map[string]*DataTable) (*DataTable, error) {
+	stmt, err := query.Parse(sql)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrQuerySyntax.Error())
+	}
+	q := &queryImpl{stmt: stmt, tables: tables}
+	return q.execute()
+}
+
+// sqlAggregations maps the SQL aggregate functions
+var sqlAggregations = map[string]AggregationType{
+	"count":        Count,
+	"sum":          Sum,
+	"avg":          Avg,
+	"min":          Min,
+	"max":          Max,
+	"median":       Median,
+	"stddev":       Stddev,
+	"variance":     Variance,
+	"group_concat": GroupConcat,
+	"any_value":    GroupAny,
+}
+
+// sqlFunctions are the functions used to render the SQL operators
+// with the SQL semantic: a comparison with NULL is NULL, NULL OR TRUE is TRUE...
+// _null(v, args...) is NULL if an argument is NULL, v otherwise.
+var sqlFunctions = map[string]*function{
+	"_and": sqlLogical("_and", func(x, y interface{}) interface{} {
+		bx, okx := cast.AsBool(x)
+		by, oky := cast.AsBool(y)
+		switch {
+		case (okx && !bx) || (oky && !by):
+			return false
+		case okx && oky:
+			return true
+		}
+		return nil
+	}),
+	"_or": sqlLogical("_or", func(x, y interface{}) interface{} {
+		bx, okx := cast.AsBool(x)
+		by, oky := cast.AsBool(y)
+		switch {
+		case (okx && bx) || (oky && by):
+			return true
+		case okx && oky:
+			return false
+		}
+		return nil
+	}),
+	"_not": &function{name: "_not", fn: reflect.ValueOf(func(x interface{}) interface{} {
+		if b, ok := cast.AsBool(x); ok {
+			return !b
+		}
+		return nil
+	})},
+	"_eq": sqlCompare("_eq", func(c int) bool { return c == 0 }),
+	"_ne": sqlCompare("_ne", func(c int) bool { return c != 0 }),
+	"_lt": sqlCompare("_lt", func(c int) bool { return c < 0 }),
+	"_le": sqlCompare("_le", func(c int) bool { return c <= 0 }),
+	"_gt": sqlCompare("_gt", func(c int) bool { return c > 0 }),
+	"_ge": sqlCompare("_ge", func(c int) bool { return c >= 0 }),
+	"_in": &function{name: "_in", fn: reflect.ValueOf(func(x interface{}, list ...interface{}) interface{} {
+		if x == nil {
+			return nil
+		}
+		for _, v := range list {
+			if c, ok := compareValues(x, v); ok && c == 0 {
+				return true
+			}
+		}
+		return false
+	})},
+	"_null": &function{name: "_null", fn: reflect.ValueOf(func(v interface{}, args ...interface{}) interface{} {
+		for _, arg := range args {
+			if arg == nil {
+				return nil
+			}
+		}
+		return v
+	})},
+}
+
+var sqlOperators = map[string]string{
+	"=":  "_eq",
+	"<>": "_ne",
+	"<":  "_lt",
+	"<=": "_le",
+	">":  "_gt",
+	">=": "_ge",
+}
+
+func sqlLogical(name string, fn func(x, y interface{}) interface{}) *function {
+	return &function{name: name, fn: reflect.ValueOf(fn)}
+}
+
+func sqlCompare(name string, test func(c int) bool) *function {
+	return &function{name: name, fn: reflect.ValueOf(func(x, y interface{}) interface{} {
+		if x == nil || y == nil {
+			return nil
+		}
+		c, ok := compareValues(x, y)
+		if !ok {
+			return nil
+		}
+		return test(c)
+	})}
+}
+
+// compareValues compares 2 values with serie.CompareValues
+// A time is compared with a value parsed as a time, ie a string.
+func compareValues(x, y interface{}) (int, bool) {
+	_, okx := x.(time.Time)
+	_, oky := y.(time.Time)
+	if okx && !oky {
+		if ty, ok := cast.AsTime(y); ok {
+			y = ty
+		}
+	} else if !okx && oky {
+		if tx, ok := cast.AsTime(x); ok {
+			x = tx
+		}
+	}
+	return serie.CompareValues(x, y)
+}
+
+// queryResolver returns the name of the column holding the value of an expression
+type queryResolver func(e query.Expr) (string, bool, error)
+
+type queryImpl struct {
+	stmt   *query.Statement
+	tables map[string]*DataTable
+	refs   []string        // table references in the FROM and JOIN clauses
+	hidden map[string]bool // hidden columns, not selected by *
+	ntemp  int
+}
+
+func (q *queryImpl) execute() (*DataTable, error) {
+	src, err := q.load(q.stmt.From)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, join := range q.stmt.Joins {
+		if src, err = q.join(src, join); err != nil {
+			return nil, err
+		}
+	}
+
+	resolve := q.sourceResolver(src)
+
+	// WHERE
+	if q.stmt.Where != nil {
+		if src, err = q.filter(src, q.stmt.Where, resolve); err != nil {
+			return nil, err
+		}
+	}
+
+	// GROUP BY & HAVING
+	if q.isAggregated() {
+		if src, resolve, err = q.aggregate(src, resolve); err != nil {
+			return nil, err
+		}
+		if q.stmt.Having != nil {
+			if src, err = q.filter(src, q.stmt.Having, resolve); err != nil {
+				return nil, err
+			}
+		}
+	} else if q.stmt.Having != nil {
+		err := errors.New("HAVING without GROUP BY or aggregation")
+		return nil, errors.Wrap(err, ErrUnsupportedQuery.Error())
+	}
+
+	// SELECT
+	out, err := q.project(src, resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	// ORDER BY
+	sorts, temps, err := q.orderBy(src, out, resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.stmt.Distinct {
+		if out, err = out.Distinct(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(sorts) > 0 {
+		out = out.Sort(sorts...)
+	}
+	for _, name := range temps {
+		out.RemoveColumn(name)
+	}
+
+	// LIMIT & OFFSET
+	if q.stmt.Offset > 0 || q.stmt.Limit >= 0 {
+		offset := q.stmt.Offset
+		if offset > out.NumRows() {
+			offset = out.NumRows()
+		}
+		size := out.NumRows() - offset
+		if q.stmt.Limit >= 0 && q.stmt.Limit < size {
+			size = q.stmt.Limit
+		}
+		out = out.Subset(offset, size)
+	}
+
+	return out, nil
+}
+
+// tempName creates a name for a temporary column
+func (q *queryImpl) tempName() string {
+	q.ntemp++
+	return fmt.Sprintf("#%d", q.ntemp)
+}
+
+// load copies a table with the columns prefixed by the table reference
+func (q *queryImpl) load(ref query.TableRef) (*DataTable, error) {
+	dt, ok := q.tables[ref.Name]
+	if !ok || dt == nil {
+		err := errors.Errorf("table '%s' not found", ref.Name)
+		return nil, errors.Wrap(err, ErrUnknownTable.Error())
+	}
+	name := ref.Ref()
+	for _, r := range q.refs {
+		if r == name {
+			err := errors.Errorf("table '%s' is referenced twice, use an alias", name)
+			return nil, errors.Wrap(err, ErrUnsupportedQuery.Error())
+		}
+	}
+	q.refs = append(q.refs, name)
+
+	if err := dt.evaluateExpressions(); err != nil {
+		return nil, err
+	}
+
+	out := New(name)
+	for _, col := range dt.cols {
+		cpy, err := cloneColumn(dt, col.name, name+"."+col.name)
+		if err != nil {
+			return nil, err
+		}
+		if col.hidden {
+			if q.hidden == nil {
+				q.hidden = make(map[string]bool)
+			}
+			q.hidden[cpy.name] = true
+		}
+		if err := out.addColumn(cpy); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// cloneColumn creates a column named {name} with the values of the column {from}
+func cloneColumn(dt *DataTable, from, name string) (*column, error) {
+	pos := dt.ColumnIndex(from)
+	if pos < 0 {
+		err := errors.Errorf("column '%s' not found", from)
+		return nil, errors.Wrap(err, ErrColumnNotFound.Error())
+	}
+	col := dt.cols[pos].plainCopy()
+	col.name = name
+	col.hidden = false
+	col.serie = dt.cols[pos].serie.Copy()
+	return col, nil
+}
+
+// join joins the table of the JOIN clause
+// The keys are copied in temporary columns because the join removes the keys of the right table.
+func (q *queryImpl) join(left *DataTable, join query.Join) (*DataTable, error) {
+	lrefs := q.refs
+	right, err := q.load(join.Table)
+	if err != nil {
+		return nil, err
+	}
+	lresolve := q.tableResolver(left, lrefs)
+	rresolve := q.tableResolver(right, q.refs[len(lrefs):])
+
+	var conds []query.Expr
+	var flatten func(e query.Expr)
+	flatten = func(e query.Expr) {
+		if b, ok := e.(*query.BinaryExpr); ok && b.Op == "AND" {
+			flatten(b.Left)
+			flatten(b.Right)
+			return
+		}
+		conds = append(conds, e)
+	}
+	flatten(join.On)
+
+	var on []JoinOn
+	var lkeys []string
+	for _, cond := range conds {
+		b, ok := cond.(*query.BinaryExpr)
+		if !ok || b.Op != "=" {
+			err := errors.Errorf("join on '%s': only equalities are supported", cond)
+			return nil, errors.Wrap(err, ErrUnsupportedQuery.Error())
+		}
+
+		lcol, lerr := resolveColumn(lresolve, b.Left)
+		rcol, rerr := resolveColumn(rresolve, b.Right)
+		if lerr != nil || rerr != nil {
+			// swapped
+			lcol, lerr = resolveColumn(lresolve, b.Right)
+			rcol, rerr = resolveColumn(rresolve, b.Left)
+		}
+		if lerr != nil || rerr != nil {
+			err := errors.Errorf("join on '%s': expected a column of each table", cond)
+			return nil, errors.Wrap(err, ErrUnsupportedQuery.Error())
+		}
+
+		lkey, rkey := q.tempName(), q.tempName()
+		if err := q.copyKeyColumn(left, lcol, lkey, 0); err != nil {
+			return nil, err
+		}
+		if err := q.copyKeyColumn(right, rcol, rkey, 1); err != nil {
+			return nil, err
+		}
+		on = append(on, JoinOn{Table: left.Name(), Field: lkey}, JoinOn{Table: right.Name(), Field: rkey})
+		lkeys = append(lkeys, lkey)
+	}
+
+	var out *DataTable
+	switch join.Type {
+	case query.InnerJoin:
+		out, err = left.InnerJoin(right, on)
+	case query.LeftJoin:
+		out, err = left.LeftJoin(right, on)
+	case query.RightJoin:
+		out, err = left.RightJoin(right, on)
+	case query.OuterJoin:
+		out, err = left.OuterJoin(right, on)
+	default:
+		err = errors.Wrap(errors.Errorf("unknown join '%v'", join.Type), ErrUnknownMode.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range lkeys {
+		out.RemoveColumn(key)
+	}
+	return out, nil
+}
+
+// sqlNullKey replaces a NULL join key: it matches no other key, as NULL = NULL is not true
+type sqlNullKey struct {
+	Side int
+	Row  int
+}
+
+// copyKeyColumn copies the join key {from} of the {side} table in a raw column
+// The rows with a NULL key are not joined, and kept with NULLs by an outer join.
+func (q *queryImpl) copyKeyColumn(dt *DataTable, from, name string, side int) error {
+	pos := dt.ColumnIndex(from)
+	if pos < 0 {
+		err := errors.Errorf("column '%s' not found", from)
+		return errors.Wrap(err, ErrColumnNotFound.Error())
+	}
+	sr := dt.cols[pos].serie
+	values := make([]interface{}, sr.Len())
+	for i := range values {
+		if values[i] = sr.Get(i); values[i] == nil {
+			values[i] = sqlNullKey{Side: side, Row: i}
+		}
+	}
+	return dt.AddColumn(name, Raw, Values(values...))
+}
+
+// resolveColumn resolves a column reference
+func resolveColumn(resolve queryResolver, e query.Expr) (string, error) {
+	if _, ok := e.(*query.ColumnRef); !ok {
+		return "", errors.Errorf("'%s' is not a column", e)
+	}
+	name, ok, err := resolve(e)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		err := errors.Errorf("column '%s' not found", e)
+		return "", errors.Wrap(err, ErrUnknownColumnRef.Error())
+	}
+	return name, nil
+}
+
+// tableResolver resolves the column references in the tables with references {refs}
+func (q *queryImpl) tableResolver(dt *DataTable, refs []string) queryResolver {
+	return func(e query.Expr) (string, bool, error) {
+		c, ok := e.(*query.ColumnRef)
+		if !ok {
+			return "", false, nil
+		}
+		if len(c.Table) > 0 {
+			name := c.Table + "." + c.Name
+			return name, dt.ColumnIndex(name) >= 0, nil
+		}
+
+		var found []string
+		for _, ref := range refs {
+			if name := ref + "." + c.Name; dt.ColumnIndex(name) >= 0 {
+				found = append(found, name)
+			}
+		}
+		switch len(found) {
+		case 0:
+			return "", false, nil
+		case 1:
+			return found[0], true, nil
+		}
+		err := errors.Errorf("column '%s' is ambiguous: %s", c.Name, strings.Join(found, ", "))
+		return "", false, errors.Wrap(err, ErrAmbiguousColumnRef.Error())
+	}
+}
+
+// sourceResolver resolves the column references in the joined tables
+func (q *queryImpl) sourceResolver(dt *DataTable) queryResolver {
+	return q.tableResolver(dt, q.refs)
+}
+
+// render renders a SQL expression to an expression of the computed columns
+func (q *queryImpl) render(e query.Expr, resolve queryResolver) (string, error) {
+	name, ok, err := resolve(e)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return "`" + name + "`", nil
+	}
+
+	switch v := e.(type) {
+	case *query.ColumnRef:
+		err := errors.Errorf("column '%s' not found", v)
+		return "", errors.Wrap(err, ErrUnknownColumnRef.Error())
+
+	case *query.Star:
+		err := errors.Errorf("unexpected '%s'", v)
+		return "", errors.Wrap(err, ErrUnsupportedQuery.Error())
+
+	case *query.Literal:
+		switch lit := v.Value.(type) {
+		case nil:
+			return "nil", nil
+		case bool:
+			return strconv.FormatBool(lit), nil
+		case float64:
+			return strconv.FormatFloat(lit, 'g', -1, 64), nil
+		case string:
+			return "\"" + strings.Replace(lit, "\"", "\\\"", -1) + "\"", nil
+		}
+
+	case *query.UnaryExpr:
+		x, err := q.render(v.X, resolve)
+		if err != nil {
+			return "", err
+		}
+		if v.Op == "NOT" {
+			return "_not(" + x + ")", nil
+		}
+		return "(" + v.Op + x + ")", nil
+
+	case *query.BinaryExpr:
+		left, err := q.render(v.Left, resolve)
+		if err != nil {
+			return "", err
+		}
+		right, err := q.render(v.Right, resolve)
+		if err != nil {
+			return "", err
+		}
+		if fn, ok := sqlOperators[v.Op]; ok {
+			return fn + "(" + left + ", " + right + ")", nil
+		}
+		switch v.Op {
+		case "AND":
+			return "_and(" + left + ", " + right + ")", nil
+		case "OR":
+			return "_or(" + left + ", " + right + ")", nil
+		}
+		if v.Op == "||" {
+			return nullable("("+left+" ~ "+right+")", left, right), nil
+		}
+		return "(" + left + " " + v.Op + " " + right + ")", nil
+
+	case *query.IsNull:
+		x, err := q.render(v.X, resolve)
+		if err != nil {
+			return "", err
+		}
+		if v.Not {
+			return "(" + x + " != nil)", nil
+		}
+		return "(" + x + " == nil)", nil
+
+	case *query.InList:
+		args := make([]string, 0, len(v.List)+1)
+		for _, item := range append([]query.Expr{v.X}, v.List...) {
+			arg, err := q.render(item, resolve)
+			if err != nil {
+				return "", err
+			}
+			args = append(args, arg)
+		}
+		in := "_in(" + strings.Join(args, ", ") + ")"
+		if v.Not {
+			return "_not(" + in + ")", nil
+		}
+		return in, nil
+
+	case *query.Call:
+		if isAggregation(v) {
+			err := errors.Errorf("aggregation '%s' is not allowed here", v)
+			return "", errors.Wrap(err, ErrUnsupportedQuery.Error())
+		}
+		name := v.Name
+		builtin := false
+		if _, ok := lookupFunction(name); !ok {
+			name = strings.ToLower(name)
+			if node, err := expr.Parse(name + "()"); err != nil || !isExprNode(node, exprBuiltinNode) {
+				err := errors.Errorf("function '%s' not found", v.Name)
+				return "", errors.Wrap(err, ErrUnknownFunction.Error())
+			}
+			builtin = true
+		}
+		args := make([]string, 0, len(v.Args))
+		for _, item := range v.Args {
+			arg, err := q.render(item, resolve)
+			if err != nil {
+				return "", err
+			}
+			args = append(args, arg)
+		}
+		call := name + "(" + strings.Join(args, ", ") + ")"
+		if builtin && !aggregateBuiltins[name] && len(args) > 0 {
+			return nullable(call, args...), nil
+		}
+		return call, nil
+	}
+
+	err = errors.Errorf("unexpected expression '%s'", e)
+	return "", errors.Wrap(err, ErrUnsupportedQuery.Error())
+}
+
+// nullable renders an expression which is NULL if one of its arguments is NULL
+func nullable(formulae string, args ...string) string {
+	for _, arg := range args {
+		if arg == "nil" {
+			return "nil"
+		}
+	}
+	return "_null(" + formulae + ", " + strings.Join(args, ", ") + ")"
+}
+
+// eval evaluates a SQL expression on each row of the table
+func (q *queryImpl) eval(dt *DataTable, e query.Expr, resolve queryResolver) ([]interface{}, error) {
+	formulae, err := q.render(e, resolve)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := expr.Parse(formulae)
+	if err != nil {
+		err = errors.Wrapf(err, "expr '%s'", e)
+		return nil, errors.Wrap(err, ErrFormulaeSyntax.Error())
+	}
+	values, err := dt.evalNode(parsed, sqlFunctions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "expr '%s'", e)
+	}
+	return values, nil
+}
+
+// materialize evaluates a SQL expression and stores the values in a new column
+func (q *queryImpl) materialize(dt *DataTable, e query.Expr, resolve queryResolver, name string) error {
+	values, err := q.eval(dt, e, resolve)
+	if err != nil {
+		return err
+	}
+	return dt.AddColumn(name, valuesColumnType(values), Values(values...))
+}
+
+// valuesColumnType finds the column type of values
+func valuesColumnType(values []interface{}) ColumnType {
+	var types []ColumnType
+	seen := make(map[ColumnType]bool)
+	for _, v := range values {
+		var typ ColumnType
+		switch v.(type) {
+		case nil:
+			continue
+		case bool:
+			typ = Bool
+		case string:
+			typ = String
+		case int:
+			typ = Int
+		case int32:
+			typ = Int32
+		case int64:
+			typ = Int64
+		case float32:
+			typ = Float32
+		case float64:
+			typ = Float64
+		case time.Time:
+			typ = Time
+		default:
+			typ = Raw
+		}
+		if !seen[typ] {
+			seen[typ] = true
+			types = append(types, typ)
+		}
+	}
+	return commonColumnType(types...)
+}
+
+// filter selects the rows where the condition is true
+func (q *queryImpl) filter(dt *DataTable, cond query.Expr, resolve queryResolver) (*DataTable, error) {
+	values, err := q.eval(dt, cond, resolve)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := whereRows(values)
+	if err != nil {
+		return nil, errors.Wrapf(err, "condition '%s'", cond)
+	}
+	return dt.pick(rows...), nil
+}
+
+// isAggregation checks if an expression is a SQL aggregation
+func isAggregation(e query.Expr) bool {
+	c, ok := e.(*query.Call)
+	if !ok {
+		return false
+	}
+	_, ok = sqlAggregations[strings.ToLower(c.Name)]
+	return ok
+}
+
+// hasAggregation checks if an expression contains a SQL aggregation
+func hasAggregation(e query.Expr) bool {
+	var found bool
+	query.Walk(e, func(e query.Expr) bool {
+		if isAggregation(e) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// isAggregated checks if the query needs a GROUP BY
+func (q *queryImpl) isAggregated() bool {
+	if len(q.stmt.GroupBy) > 0 || hasAggregation(q.stmt.Having) {
+		return true
+	}
+	for _, f := range q.stmt.Fields {
+		if hasAggregation(f.Expr) {
+			return true
+		}
+	}
+	for _, o := range q.stmt.OrderBy {
+		if hasAggregation(o.Expr) {
+			return true
+		}
+	}
+	return false
+}
+
+// aggregate groups the rows and computes the aggregations.
+// The returned resolver resolves the group by keys and the aggregations in the aggregated table.
+func (q *queryImpl) aggregate(src *DataTable, resolve queryResolver) (*DataTable, queryResolver, error) {
+	cols := make(map[string]string) // expr => column in aggregated table
+
+	// keys
+	var keys []GroupBy
+	for _, e := range q.stmt.GroupBy {
+		// select alias
+		var alias string
+		if c, ok := e.(*query.ColumnRef); ok && len(c.Table) == 0 {
+			if _, found, _ := resolve(c); !found {
+				for _, f := range q.stmt.Fields {
+					if f.Alias == c.Name {
+						alias = c.Name
+						e = f.Expr
+						break
+					}
+				}
+			}
+		}
+		if hasAggregation(e) {
+			err := errors.Errorf("group by '%s': aggregation is not allowed", e)
+			return nil, nil, errors.Wrap(err, ErrUnsupportedQuery.Error())
+		}
+
+		name, ok, err := resolve(e)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			name = q.tempName()
+			if err := q.materialize(src, e, resolve, name); err != nil {
+				return nil, nil, err
+			}
+		}
+		cols[e.String()] = name
+		if len(alias) > 0 {
+			cols[alias] = name
+		}
+
+		key := name
+		keys = append(keys, GroupBy{
+			Name: name,
+			Keyer: func(row Row) (interface{}, bool) {
+				return row[key], true
+			},
+		})
+	}
+
+	// aggregations
+	var aggs []AggregateBy
+	counts := make(map[string]string) // aggregation => count of its values
+	var visit func(e query.Expr) bool
+	var err error
+	visit = func(e query.Expr) bool {
+		if err != nil {
+			return false
+		}
+		c, ok := e.(*query.Call)
+		if !ok || !isAggregation(c) {
+			return true
+		}
+		if _, ok := cols[c.String()]; ok {
+			return false
+		}
+
+		agg := AggregateBy{Type: sqlAggregations[strings.ToLower(c.Name)], As: q.tempName()}
+		if c.Distinct {
+			if agg.Type != Count {
+				err = errors.Errorf("'%s': DISTINCT is only supported with count", c)
+				err = errors.Wrap(err, ErrUnsupportedQuery.Error())
+				return false
+			}
+			agg.Type = CountDistinct
+		}
+		if len(c.Args) != 1 {
+			err = errors.Errorf("'%s': expected one argument", c)
+			err = errors.Wrap(err, ErrUnsupportedQuery.Error())
+			return false
+		}
+
+		arg := c.Args[0]
+		if _, ok := arg.(*query.Star); ok {
+			arg = &query.Literal{Value: 1.0}
+		}
+		if hasAggregation(arg) {
+			err = errors.Errorf("'%s': nested aggregation", c)
+			err = errors.Wrap(err, ErrUnsupportedQuery.Error())
+			return false
+		}
+		name, ok, rerr := resolve(arg)
+		if rerr != nil {
+			err = rerr
+			return false
+		}
+		if !ok {
+			name = q.tempName()
+			if err = q.materialize(src, arg, resolve, name); err != nil {
+				return false
+			}
+		}
+		agg.Field = name
+		aggs = append(aggs, agg)
+		cols[c.String()] = agg.As
+		if agg.Type != Count && agg.Type != CountDistinct {
+			count := AggregateBy{Type: Count, Field: name, As: q.tempName()}
+			aggs = append(aggs, count)
+			counts[agg.As] = count.As
+		}
+		return false
+	}
+	for _, f := range q.stmt.Fields {
+		query.Walk(f.Expr, visit)
+	}
+	query.Walk(q.stmt.Having, visit)
+	for _, o := range q.stmt.OrderBy {
+		query.Walk(o.Expr, visit)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var out *DataTable
+	if len(keys) == 0 {
+		out, err = src.Aggregate(aggs...)
+	} else {
+		var groups *Groups
+		if groups, err = src.GroupBy(keys...); err == nil {
+			out, err = groups.Aggregate(aggs...)
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// as in SQL, an aggregation of no values is NULL, except count
+	for name, count := range counts {
+		sr := out.cols[out.ColumnIndex(name)].serie
+		n := out.cols[out.ColumnIndex(count)].serie
+		for i := 0; i < out.nrows; i++ {
+			if c, _ := cast.AsInt64(n.Get(i)); c == 0 {
+				sr.Set(i, nil)
+			}
+		}
+		out.RemoveColumn(count)
+	}
+
+	return out, func(e query.Expr) (string, bool, error) {
+		if name, ok := cols[e.String()]; ok {
+			return name, true, nil
+		}
+		if _, ok := e.(*query.ColumnRef); ok {
+			name, ok, err := resolve(e)
+			if err != nil || !ok {
+				return "", ok, err
+			}
+			if out.ColumnIndex(name) >= 0 {
+				return name, true, nil
+			}
+			err = errors.Errorf("column '%s' must appear in the GROUP BY clause or be used in an aggregation", e)
+			return "", false, errors.Wrap(err, ErrUnsupportedQuery.Error())
+		}
+		return "", false, nil
+	}, nil
+}
+
+// project creates the output table with the selected fields
+func (q *queryImpl) project(src *DataTable, resolve queryResolver) (*DataTable, error) {
+	out := New(q.stmt.From.Name)
+
+	type field struct {
+		name   string
+		column string // column in src
+		expr   query.Expr
+		alias  bool // name is an explicit alias
+	}
+
+	var fields []field
+	for _, f := range q.stmt.Fields {
+		star, ok := f.Expr.(*query.Star)
+		if !ok {
+			name, ok, err := resolve(f.Expr)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				name = ""
+			}
+			fields = append(fields, field{name: f.Name(), column: name, expr: f.Expr, alias: len(f.Alias) > 0})
+			continue
+		}
+
+		if q.isAggregated() {
+			err := errors.Errorf("'%s' is not allowed with GROUP BY", star)
+			return nil, errors.Wrap(err, ErrUnsupportedQuery.Error())
+		}
+		found := false
+		for _, col := range src.cols {
+			if q.hidden[col.name] || strings.HasPrefix(col.name, "#") {
+				continue
+			}
+			if len(star.Table) > 0 && !strings.HasPrefix(col.name, star.Table+".") {
+				continue
+			}
+			found = true
+			fields = append(fields, field{name: col.name[strings.Index(col.name, ".")+1:], column: col.name})
+		}
+		if !found {
+			err := errors.Errorf("table '%s' not found", star.Table)
+			return nil, errors.Wrap(err, ErrUnknownTable.Error())
+		}
+	}
+
+	// the duplicate names are qualified, except the aliases
+	count := make(map[string]int, len(fields))
+	for _, f := range fields {
+		count[f.name]++
+	}
+	for i, f := range fields {
+		if count[f.name] > 1 && !f.alias && len(f.column) > 0 && !strings.HasPrefix(f.column, "#") {
+			fields[i].name = f.column
+		}
+	}
+	// the remaining duplicates get a suffix, ie id_1
+	used := make(map[string]bool, len(fields))
+	for i, f := range fields {
+		name := f.name
+		for k := 1; used[name]; k++ {
+			name = fmt.Sprintf("%s_%d", f.name, k)
+		}
+		used[name] = true
+		fields[i].name = name
+	}
+
+	for _, f := range fields {
+		var err error
+		if len(f.column) > 0 {
+			var col *column
+			if col, err = cloneColumn(src, f.column, f.name); err == nil {
+				err = out.addColumn(col)
+			}
+		} else {
+			var values []interface{}
+			if values, err = q.eval(src, f.expr, resolve); err == nil {
+				err = out.AddColumn(f.name, valuesColumnType(values), Values(values...))
+			}
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "field '%s'", f.name)
+		}
+	}
+
+	return out, nil
+}
+
+// orderBy creates the sorts of the ORDER BY clause
+// The sort keys which are not selected are added in temporary columns.
+func (q *queryImpl) orderBy(src, out *DataTable, resolve queryResolver) ([]SortBy, []string, error) {
+	var sorts []SortBy
+	var temps []string
+	for _, o := range q.stmt.OrderBy {
+		var name string
+		switch v := o.Expr.(type) {
+		case *query.Literal:
+			// position
+			if f, ok := v.Value.(float64); ok {
+				visible := out.Columns()
+				if pos := int(f); float64(pos) != f || pos < 1 || pos > len(visible) {
+					err := errors.Errorf("order by %s: position out of range", v)
+					return nil, nil, errors.Wrap(err, ErrOutOfRange.Error())
+				} else {
+					name = visible[pos-1]
+				}
+			}
+		case *query.ColumnRef:
+			// output column
+			if len(v.Table) == 0 && out.ColumnIndex(v.Name) >= 0 {
+				name = v.Name
+			}
+		}
+
+		if len(name) == 0 {
+			// with DISTINCT, a sort key must be a selected expression
+			if q.stmt.Distinct && !q.isSelected(o.Expr) {
+				err := errors.Errorf("order by %s: expression must appear in the select list with DISTINCT", o.Expr)
+				return nil, nil, errors.Wrap(err, ErrUnsupportedQuery.Error())
+			}
+			name = q.tempName()
+			values, err := q.eval(src, o.Expr, resolve)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := out.AddColumn(name, valuesColumnType(values), Values(values...), ColumnHidden(true)); err != nil {
+				return nil, nil, err
+			}
+			temps = append(temps, name)
+		}
+		sorts = append(sorts, SortBy{Column: name, Desc: o.Desc})
+	}
+	return sorts, temps, nil
+}
+
+// isSelected returns true if the expression is a field of the SELECT clause
+func (q *queryImpl) isSelected(e query.Expr) bool {
+	for _, f := range q.stmt.Fields {
+		if f.Expr.String() == e.String() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Statement is a parsed SELECT query
+type Statement struct {
+	Distinct bool
+	Fields   []Field
+	From     TableRef
+	Joins    []Join
+	Where    Expr
+	GroupBy  []Expr
+	Having   Expr
+	OrderBy  []Order
+	Limit    int // -1 if no limit
+	Offset   int
+}
+
+// Field is a selected expression with its alias
+type Field struct {
+	Expr  Expr
+	Alias string
+}
+
+// Name returns the output name of the field
+func (f Field) Name() string {
+	if len(f.Alias) > 0 {
+		return f.Alias
+	}
+	if c, ok := f.Expr.(*ColumnRef); ok {
+		return c.Name
+	}
+	return f.Expr.String()
+}
+
+// TableRef is a table in the FROM or JOIN clauses
+type TableRef struct {
+	Name  string
+	Alias string
+}
+
+// Ref returns the name used to reference the table in the query
+func (t TableRef) Ref() string {
+	if len(t.Alias) > 0 {
+		return t.Alias
+	}
+	return t.Name
+}
+
+// JoinType defines the join mode
+type JoinType uint8
+
+const (
+	InnerJoin JoinType = iota
+	LeftJoin
+	RightJoin
+	OuterJoin
+)
+
+// Join is a JOIN clause
+type Join struct {
+	Type  JoinType
+	Table TableRef
+	On    Expr
+}
+
+// Order is an ORDER BY item
+type Order struct {
+	Expr Expr
+	Desc bool
+}
+
+// Expr is an expression
+type Expr interface {
+	String() string
+}
+
+// Star is "*" or "table.*"
+type Star struct {
+	Table string
+}
+
+func (e *Star) String() string {
+	if len(e.Table) > 0 {
+		return e.Table + ".*"
+	}
+	return "*"
+}
+
+// ColumnRef is a reference to a column, with an optional table
+type ColumnRef struct {
+	Table string
+	Name  string
+}
+
+func (e *ColumnRef) String() string {
+	if len(e.Table) > 0 {
+		return e.Table + "." + e.Name
+	}
+	return e.Name
+}
+
+// Literal is a constant value: nil, bool, float64 or string
+type Literal struct {
+	Value interface{}
+}
+
+func (e *Literal) String() string {
+	switch v := e.Value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return "'" + strings.Replace(v, "'", "''", -1) + "'"
+	}
+	return ""
+}
+
+// UnaryExpr is "-x" or "NOT x"
+type UnaryExpr struct {
+	Op string
+	X  Expr
+}
+
+func (e *UnaryExpr) String() string {
+	if e.Op == "NOT" {
+		return "NOT " + e.X.String()
+	}
+	return e.Op + e.X.String()
+}
+
+// BinaryExpr is "x op y"
+// Op is one of AND, OR, =, <>, <, <=, >, >=, +, -, *, /, %, ||
+type BinaryExpr struct {
+	Op    string
+	Left  Expr
+	Right Expr
+}
+
+func (e *BinaryExpr) String() string {
+	return "(" + e.Left.String() + " " + e.Op + " " + e.Right.String() + ")"
+}
+
+// IsNull is "x IS [NOT] NULL"
+type IsNull struct {
+	X   Expr
+	Not bool
+}
+
+func (e *IsNull) String() string {
+	if e.Not {
+		return e.X.String() + " IS NOT NULL"
+	}
+	return e.X.String() + " IS NULL"
+}
+
+// InList is "x [NOT] IN (a, b, ...)"
+type InList struct {
+	X    Expr
+	List []Expr
+	Not  bool
+}
+
+func (e *InList) String() string {
+	items := make([]string, len(e.List))
+	for i, item := range e.List {
+		items[i] = item.String()
+	}
+	op := " IN ("
+	if e.Not {
+		op = " NOT IN ("
+	}
+	return e.X.String() + op + strings.Join(items, ", ") + ")"
+}
+
+// Call is a function call
+type Call struct {
+	Name     string
+	Args     []Expr
+	Distinct bool
+}
+
+func (e *Call) String() string {
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = arg.String()
+	}
+	if e.Distinct {
+		return e.Name + "(DISTINCT " + strings.Join(args, ", ") + ")"
+	}
+	return e.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Walk calls fn on the expression and its children
+// Walk does not visit the children if fn returns false
+func Walk(e Expr, fn func(e Expr) bool) {
+	if e == nil || !fn(e) {
+		return
+	}
+	switch v := e.(type) {
+	case *UnaryExpr:
+		Walk(v.X, fn)
+	case *BinaryExpr:
+		Walk(v.Left, fn)
+		Walk(v.Right, fn)
+	case *IsNull:
+		Walk(v.X, fn)
+	case *InList:
+		Walk(v.X, fn)
+		for _, item := range v.List {
+			Walk(item, fn)
+		}
+	case *Call:
+		for _, arg := range v.Args {
+			Walk(arg, fn)
+		}
+	}
+}
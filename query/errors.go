@@ -0,0 +1,11 @@
+package query
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Errors in parser.go
+var (
+	ErrSyntax     = errors.New("syntax error")
+	ErrEmptyQuery = errors.New("empty query")
+)
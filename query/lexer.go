@@ -0,0 +1,163 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+type tokenKind uint8
+
+const (
+	tokEOF tokenKind = iota
+	tokKeyword
+	tokIdent
+	tokNumber
+	tokString
+	tokOperator
+	tokPunct
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+var keywords = map[string]bool{
+	"SELECT":   true,
+	"DISTINCT": true,
+	"FROM":     true,
+	"AS":       true,
+	"WHERE":    true,
+	"GROUP":    true,
+	"BY":       true,
+	"HAVING":   true,
+	"ORDER":    true,
+	"ASC":      true,
+	"DESC":     true,
+	"LIMIT":    true,
+	"OFFSET":   true,
+	"JOIN":     true,
+	"INNER":    true,
+	"LEFT":     true,
+	"RIGHT":    true,
+	"FULL":     true,
+	"OUTER":    true,
+	"ON":       true,
+	"AND":      true,
+	"OR":       true,
+	"NOT":      true,
+	"IS":       true,
+	"NULL":     true,
+	"TRUE":     true,
+	"FALSE":    true,
+	"IN":       true,
+}
+
+// lex splits the query into tokens
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			// comment
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				i++
+				if i < len(runes) && (runes[i] == '+' || runes[i] == '-') {
+					i++
+				}
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, token{kind: tokNumber, value: string(runes[start:i]), pos: start})
+
+		case r == '\'':
+			// string, '' is an escaped quote
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, errors.Wrapf(ErrSyntax, "unterminated string at %d", start)
+				}
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						sb.WriteRune('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: tokString, value: sb.String(), pos: start})
+
+		case r == '"' || r == '`':
+			// quoted identifier
+			i++
+			for i < len(runes) && runes[i] != r {
+				i++
+			}
+			if i >= len(runes) {
+				return nil, errors.Wrapf(ErrSyntax, "unterminated identifier at %d", start)
+			}
+			tokens = append(tokens, token{kind: tokIdent, value: string(runes[start+1 : i]), pos: start})
+			i++
+
+		case r == '_' || unicode.IsLetter(r):
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			word := string(runes[start:i])
+			if upper := strings.ToUpper(word); keywords[upper] {
+				tokens = append(tokens, token{kind: tokKeyword, value: upper, pos: start})
+			} else {
+				tokens = append(tokens, token{kind: tokIdent, value: word, pos: start})
+			}
+
+		case strings.ContainsRune("(),.;", r):
+			i++
+			tokens = append(tokens, token{kind: tokPunct, value: string(r), pos: start})
+
+		case strings.ContainsRune("=<>!+-*/%|", r):
+			i++
+			if i < len(runes) {
+				switch two := string(runes[start : i+1]); two {
+				case "<=", ">=", "<>", "!=", "||":
+					i++
+				}
+			}
+			op := string(runes[start:i])
+			if op == "!" || op == "|" {
+				return nil, errors.Wrapf(ErrSyntax, "unexpected %q at %d", op, start)
+			}
+			tokens = append(tokens, token{kind: tokOperator, value: op, pos: start})
+
+		default:
+			return nil, errors.Wrapf(ErrSyntax, "unexpected %q at %d", r, start)
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, pos: len(runes)})
+	return tokens, nil
+}
@@ -0,0 +1,540 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse parses a SELECT query
+// Syntax:
+//
+//	SELECT [DISTINCT] fields
+//	FROM table [[AS] alias]
+//	[[INNER|LEFT [OUTER]|RIGHT [OUTER]|FULL [OUTER]|OUTER] JOIN table [[AS] alias] ON condition]...
+//	[WHERE condition]
+//	[GROUP BY expr, ...]
+//	[HAVING condition]
+//	[ORDER BY expr [ASC|DESC], ...]
+//	[LIMIT n] [OFFSET n]
+func Parse(sql string) (*Statement, error) {
+	if len(strings.TrimSpace(sql)) == 0 {
+		return nil, ErrEmptyQuery
+	}
+
+	tokens, err := lex(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	stmt, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+	if p.is(tokPunct, ";") {
+		p.next()
+	}
+	if !p.is(tokEOF, "") {
+		return nil, p.unexpected()
+	}
+	return stmt, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// is checks the kind and the value of the current token
+// an empty value matches any value
+func (p *parser) is(kind tokenKind, value string) bool {
+	tok := p.peek()
+	return tok.kind == kind && (len(value) == 0 || tok.value == value)
+}
+
+// accept consumes the current token if it matches
+func (p *parser) accept(kind tokenKind, value string) bool {
+	if p.is(kind, value) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// expect consumes the current token or returns an error
+func (p *parser) expect(kind tokenKind, value string) (token, error) {
+	if !p.is(kind, value) {
+		return token{}, p.unexpected()
+	}
+	return p.next(), nil
+}
+
+func (p *parser) unexpected() error {
+	tok := p.peek()
+	if tok.kind == tokEOF {
+		return errors.Wrap(ErrSyntax, "unexpected end of query")
+	}
+	return errors.Wrapf(ErrSyntax, "unexpected %q at %d", tok.value, tok.pos)
+}
+
+func (p *parser) parseSelect() (*Statement, error) {
+	if _, err := p.expect(tokKeyword, "SELECT"); err != nil {
+		return nil, err
+	}
+
+	stmt := &Statement{Limit: -1}
+	stmt.Distinct = p.accept(tokKeyword, "DISTINCT")
+
+	// Fields
+	for {
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Fields = append(stmt.Fields, field)
+		if !p.accept(tokPunct, ",") {
+			break
+		}
+	}
+
+	// From
+	if _, err := p.expect(tokKeyword, "FROM"); err != nil {
+		return nil, err
+	}
+	from, err := p.parseTable()
+	if err != nil {
+		return nil, err
+	}
+	stmt.From = from
+
+	// Joins
+	for {
+		typ, ok, err := p.parseJoinType()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		table, err := p.parseTable()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokKeyword, "ON"); err != nil {
+			return nil, err
+		}
+		on, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Joins = append(stmt.Joins, Join{Type: typ, Table: table, On: on})
+	}
+
+	// Where
+	if p.accept(tokKeyword, "WHERE") {
+		if stmt.Where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Group by
+	if p.accept(tokKeyword, "GROUP") {
+		if _, err := p.expect(tokKeyword, "BY"); err != nil {
+			return nil, err
+		}
+		if stmt.GroupBy, err = p.parseExprList(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Having
+	if p.accept(tokKeyword, "HAVING") {
+		if stmt.Having, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Order by
+	if p.accept(tokKeyword, "ORDER") {
+		if _, err := p.expect(tokKeyword, "BY"); err != nil {
+			return nil, err
+		}
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			order := Order{Expr: e}
+			if p.accept(tokKeyword, "DESC") {
+				order.Desc = true
+			} else {
+				p.accept(tokKeyword, "ASC")
+			}
+			stmt.OrderBy = append(stmt.OrderBy, order)
+			if !p.accept(tokPunct, ",") {
+				break
+			}
+		}
+	}
+
+	// Limit & offset
+	if p.accept(tokKeyword, "LIMIT") {
+		if stmt.Limit, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept(tokKeyword, "OFFSET") {
+		if stmt.Offset, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) parseField() (Field, error) {
+	var field Field
+
+	if p.accept(tokOperator, "*") {
+		field.Expr = &Star{}
+		return field, nil
+	}
+
+	// table.*
+	if p.is(tokIdent, "") && p.pos+2 < len(p.tokens) && p.tokens[p.pos+1].value == "." && p.tokens[p.pos+2].value == "*" {
+		field.Expr = &Star{Table: p.next().value}
+		p.next()
+		p.next()
+		return field, nil
+	}
+
+	e, err := p.parseExpr()
+	if err != nil {
+		return field, err
+	}
+	field.Expr = e
+
+	if p.accept(tokKeyword, "AS") {
+		tok, err := p.expect(tokIdent, "")
+		if err != nil {
+			return field, err
+		}
+		field.Alias = tok.value
+	} else if p.is(tokIdent, "") {
+		field.Alias = p.next().value
+	}
+	return field, nil
+}
+
+func (p *parser) parseTable() (TableRef, error) {
+	var table TableRef
+	tok, err := p.expect(tokIdent, "")
+	if err != nil {
+		return table, err
+	}
+	table.Name = tok.value
+
+	if p.accept(tokKeyword, "AS") {
+		tok, err := p.expect(tokIdent, "")
+		if err != nil {
+			return table, err
+		}
+		table.Alias = tok.value
+	} else if p.is(tokIdent, "") {
+		table.Alias = p.next().value
+	}
+	return table, nil
+}
+
+// parseJoinType parses [INNER|LEFT [OUTER]|RIGHT [OUTER]|FULL [OUTER]|OUTER] JOIN
+func (p *parser) parseJoinType() (JoinType, bool, error) {
+	typ := InnerJoin
+	switch {
+	case p.accept(tokKeyword, "JOIN"):
+		return InnerJoin, true, nil
+	case p.accept(tokKeyword, "INNER"):
+	case p.accept(tokKeyword, "LEFT"):
+		typ = LeftJoin
+		p.accept(tokKeyword, "OUTER")
+	case p.accept(tokKeyword, "RIGHT"):
+		typ = RightJoin
+		p.accept(tokKeyword, "OUTER")
+	case p.accept(tokKeyword, "FULL"):
+		typ = OuterJoin
+		p.accept(tokKeyword, "OUTER")
+	case p.accept(tokKeyword, "OUTER"):
+		typ = OuterJoin
+	default:
+		return typ, false, nil
+	}
+	if _, err := p.expect(tokKeyword, "JOIN"); err != nil {
+		return typ, false, err
+	}
+	return typ, true, nil
+}
+
+func (p *parser) parseInt() (int, error) {
+	tok, err := p.expect(tokNumber, "")
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(tok.value)
+	if err != nil || n < 0 {
+		return 0, errors.Wrapf(ErrSyntax, "expected a positive integer, got %q at %d", tok.value, tok.pos)
+	}
+	return n, nil
+}
+
+func (p *parser) parseExprList() ([]Expr, error) {
+	var list []Expr
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+		if !p.accept(tokPunct, ",") {
+			return list, nil
+		}
+	}
+}
+
+// parseExpr parses an expression
+// Precedence: OR < AND < NOT < comparison < + - || < * / % < unary -
+func (p *parser) parseExpr() (Expr, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokKeyword, "OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: "OR", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokKeyword, "AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: "AND", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if p.accept(tokKeyword, "NOT") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryExpr{Op: "NOT", X: x}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok := p.peek(); {
+	case tok.kind == tokOperator && strings.Contains(" = <> != < <= > >= ", " "+tok.value+" "):
+		p.next()
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		op := tok.value
+		if op == "!=" {
+			op = "<>"
+		}
+		return &BinaryExpr{Op: op, Left: left, Right: right}, nil
+
+	case p.accept(tokKeyword, "IS"):
+		not := p.accept(tokKeyword, "NOT")
+		if _, err := p.expect(tokKeyword, "NULL"); err != nil {
+			return nil, err
+		}
+		return &IsNull{X: left, Not: not}, nil
+
+	case p.is(tokKeyword, "IN") || (p.is(tokKeyword, "NOT") && p.tokens[p.pos+1].value == "IN"):
+		not := p.accept(tokKeyword, "NOT")
+		p.next()
+		if _, err := p.expect(tokPunct, "("); err != nil {
+			return nil, err
+		}
+		list, err := p.parseExprList()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokPunct, ")"); err != nil {
+			return nil, err
+		}
+		return &InList{X: left, List: list, Not: not}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (Expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.is(tokOperator, "+") || p.is(tokOperator, "-") || p.is(tokOperator, "||") {
+		op := p.next().value
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.is(tokOperator, "*") || p.is(tokOperator, "/") || p.is(tokOperator, "%") {
+		op := p.next().value
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.is(tokOperator, "-") || p.is(tokOperator, "+") {
+		op := p.next().value
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "+" {
+			return x, nil
+		}
+		if lit, ok := x.(*Literal); ok {
+			if f, ok := lit.Value.(float64); ok {
+				return &Literal{Value: -f}, nil
+			}
+		}
+		return &UnaryExpr{Op: op, X: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	tok := p.peek()
+
+	switch tok.kind {
+	case tokNumber:
+		p.next()
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, errors.Wrapf(ErrSyntax, "bad number %q at %d", tok.value, tok.pos)
+		}
+		return &Literal{Value: f}, nil
+
+	case tokString:
+		p.next()
+		return &Literal{Value: tok.value}, nil
+
+	case tokKeyword:
+		switch tok.value {
+		case "NULL":
+			p.next()
+			return &Literal{}, nil
+		case "TRUE":
+			p.next()
+			return &Literal{Value: true}, nil
+		case "FALSE":
+			p.next()
+			return &Literal{Value: false}, nil
+		}
+
+	case tokPunct:
+		if tok.value == "(" {
+			p.next()
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(tokPunct, ")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		}
+
+	case tokIdent:
+		p.next()
+
+		// function call
+		if p.accept(tokPunct, "(") {
+			call := &Call{Name: tok.value}
+			if p.accept(tokPunct, ")") {
+				return call, nil
+			}
+			if p.accept(tokOperator, "*") {
+				call.Args = []Expr{&Star{}}
+			} else {
+				call.Distinct = p.accept(tokKeyword, "DISTINCT")
+				args, err := p.parseExprList()
+				if err != nil {
+					return nil, err
+				}
+				call.Args = args
+			}
+			if _, err := p.expect(tokPunct, ")"); err != nil {
+				return nil, err
+			}
+			return call, nil
+		}
+
+		// table.column
+		if p.accept(tokPunct, ".") {
+			col, err := p.expect(tokIdent, "")
+			if err != nil {
+				return nil, err
+			}
+			return &ColumnRef{Table: tok.value, Name: col.value}, nil
+		}
+
+		return &ColumnRef{Name: tok.value}, nil
+	}
+
+	return nil, p.unexpected()
+}
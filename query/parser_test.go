@@ -0,0 +1,83 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable/query"
+)
+
+func TestParse(t *testing.T) {
+	stmt, err := query.Parse(`
+		SELECT DISTINCT c.ville AS city, count(*) total, sum(o.prix_total) / 2
+		FROM customers c
+		LEFT JOIN orders AS o ON o.user_id = c.id AND o.shop = c.shop
+		WHERE o.prix_total >= 100 AND NOT c.nom IS NULL -- comment
+		GROUP BY c.ville
+		HAVING count(DISTINCT o.num_facture) > 1
+		ORDER BY total DESC, 1
+		LIMIT 10 OFFSET 5;
+	`)
+	assert.NoError(t, err)
+	assert.NotNil(t, stmt)
+
+	assert.True(t, stmt.Distinct)
+	assert.Len(t, stmt.Fields, 3)
+	assert.Equal(t, "city", stmt.Fields[0].Name())
+	assert.Equal(t, &query.ColumnRef{Table: "c", Name: "ville"}, stmt.Fields[0].Expr)
+	assert.Equal(t, "total", stmt.Fields[1].Name())
+	assert.Equal(t, "count(*)", stmt.Fields[1].Expr.String())
+	assert.Equal(t, "(sum(o.prix_total) / 2)", stmt.Fields[2].Name())
+
+	assert.Equal(t, query.TableRef{Name: "customers", Alias: "c"}, stmt.From)
+	assert.Len(t, stmt.Joins, 1)
+	assert.Equal(t, query.LeftJoin, stmt.Joins[0].Type)
+	assert.Equal(t, query.TableRef{Name: "orders", Alias: "o"}, stmt.Joins[0].Table)
+	assert.Equal(t, "((o.user_id = c.id) AND (o.shop = c.shop))", stmt.Joins[0].On.String())
+
+	assert.Equal(t, "((o.prix_total >= 100) AND NOT c.nom IS NULL)", stmt.Where.String())
+	assert.Len(t, stmt.GroupBy, 1)
+	assert.Equal(t, "c.ville", stmt.GroupBy[0].String())
+	assert.Equal(t, "(count(DISTINCT o.num_facture) > 1)", stmt.Having.String())
+
+	assert.Len(t, stmt.OrderBy, 2)
+	assert.Equal(t, query.Order{Expr: &query.ColumnRef{Name: "total"}, Desc: true}, stmt.OrderBy[0])
+	assert.Equal(t, query.Order{Expr: &query.Literal{Value: 1.0}}, stmt.OrderBy[1])
+	assert.Equal(t, 10, stmt.Limit)
+	assert.Equal(t, 5, stmt.Offset)
+}
+
+func TestParseExpr(t *testing.T) {
+	stmt, err := query.Parse("select *, t.*, -a + b * (c - 2) % 3, `my col` || 'it''s', x NOT IN (1, 2), upper(\"name\") != 'A' OR y <> -1.5e2 from t")
+	assert.NoError(t, err)
+	assert.Equal(t, -1, stmt.Limit)
+	assert.Len(t, stmt.Fields, 6)
+	assert.Equal(t, &query.Star{}, stmt.Fields[0].Expr)
+	assert.Equal(t, &query.Star{Table: "t"}, stmt.Fields[1].Expr)
+	assert.Equal(t, "(-a + ((b * (c - 2)) % 3))", stmt.Fields[2].Expr.String())
+	assert.Equal(t, "(my col || 'it''s')", stmt.Fields[3].Expr.String())
+	assert.Equal(t, "x NOT IN (1, 2)", stmt.Fields[4].Expr.String())
+	assert.Equal(t, "((upper(name) <> 'A') OR (y <> -150))", stmt.Fields[5].Expr.String())
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, sql := range []string{
+		"",
+		"UPDATE t SET a = 1",
+		"SELECT FROM t",
+		"SELECT a",
+		"SELECT a FROM",
+		"SELECT a FROM t WHERE",
+		"SELECT a FROM t JOIN u",
+		"SELECT a FROM t LIMIT -1",
+		"SELECT 'a FROM t",
+		"SELECT a FROM t ORDER a",
+		"SELECT a FROM t extra tokens",
+		"SELECT a ! b FROM t",
+		"SELECT (a FROM t",
+	} {
+		stmt, err := query.Parse(sql)
+		assert.Error(t, err, sql)
+		assert.Nil(t, stmt, sql)
+	}
+}
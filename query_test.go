@@ -0,0 +1,261 @@
+package datatable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func TestQuerySelect(t *testing.T) {
+	sales := sampleForGroups()
+	tables := map[string]*datatable.DataTable{"sales": sales}
+
+	dt, err := datatable.Query("SELECT * FROM sales WHERE amount >= 15 AND region <> 'east' ORDER BY amount DESC LIMIT 2", tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"region", "city", "amount",
+		"south", "marseille", 30,
+		"north", "amiens", 20,
+	)
+
+	dt, err = datatable.Query("SELECT upper(city) AS name, amount * 2 AS double FROM sales s WHERE s.region IN ('north', 'east') ORDER BY city LIMIT 2 OFFSET 1", tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"name", "double",
+		"LILLE", 20.0,
+		"METZ", 16.0,
+	)
+
+	dt, err = datatable.Query("SELECT DISTINCT region FROM sales ORDER BY 1", tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"region",
+		"east",
+		"north",
+		"south",
+	)
+
+	// a sort key of a DISTINCT query can be a selected expression
+	dt, err = datatable.Query("SELECT DISTINCT upper(region) AS r FROM sales ORDER BY upper(region) DESC", tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"r",
+		"SOUTH",
+		"NORTH",
+		"EAST",
+	)
+
+	// the source table is not modified
+	assert.Equal(t, []string{"region", "city", "amount"}, sales.Columns())
+	assert.Equal(t, 6, sales.NumRows())
+}
+
+func TestQuerySelectDuplicates(t *testing.T) {
+	tables := map[string]*datatable.DataTable{"sales": sampleForGroups()}
+
+	dt, err := datatable.Query("SELECT city, city, amount FROM sales WHERE amount > 20", tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"sales.city", "sales.city_1", "amount",
+		"marseille", "marseille", 30,
+	)
+
+	dt, err = datatable.Query("SELECT amount * 2 AS x, amount * 3 AS x FROM sales WHERE amount > 20", tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"x", "x_1",
+		60.0, 90.0,
+	)
+
+	// the aliases are kept, not qualified
+	dt, err = datatable.Query("SELECT city AS x, city AS x, amount FROM sales WHERE amount > 20", tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"x", "x_1", "amount",
+		"marseille", "marseille", 30,
+	)
+}
+
+func TestQueryNull(t *testing.T) {
+	dt := datatable.New("people")
+	dt.AddColumn("name", datatable.String, datatable.Values("ada", nil))
+	tables := map[string]*datatable.DataTable{"people": dt}
+
+	out, err := datatable.Query("SELECT upper(name) AS up, name || '!' AS shout, length(name) AS len, upper(NULL) AS none, NULL || name AS none2 FROM people", tables)
+	assert.NoError(t, err)
+	assert.Equal(t, []datatable.Row{
+		{"up": "ADA", "shout": "ada!", "len": 3, "none": nil, "none2": nil},
+		{"up": nil, "shout": nil, "len": nil, "none": nil, "none2": nil},
+	}, out.Rows())
+}
+
+func TestQueryGroupBy(t *testing.T) {
+	tables := map[string]*datatable.DataTable{"sales": sampleForGroups()}
+
+	dt, err := datatable.Query(`
+		SELECT region, count(*) AS n, sum(amount) AS total, max(amount) - min(amount) AS spread
+		FROM sales
+		GROUP BY region
+		HAVING count(*) > 1
+		ORDER BY total DESC
+	`, tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"region", "n", "total", "spread",
+		"south", int64(3), 50.0, 25.0,
+		"north", int64(2), 30.0, 10.0,
+	)
+
+	dt, err = datatable.Query("SELECT count(DISTINCT region) AS regions, avg(amount) FROM sales", tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"regions", "avg(amount)",
+		int64(3), 14.666666666666666,
+	)
+
+	dt, err = datatable.Query("SELECT upper(region) AS r, sum(amount) total FROM sales GROUP BY r ORDER BY sum(amount)", tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"r", "total",
+		"EAST", 8.0,
+		"NORTH", 30.0,
+		"SOUTH", 50.0,
+	)
+
+	_, err = datatable.Query("SELECT city, sum(amount) FROM sales GROUP BY region", tables)
+	assert.Error(t, err)
+}
+
+func TestQueryJoin(t *testing.T) {
+	customers, orders := sampleForJoin()
+	tables := map[string]*datatable.DataTable{"customers": customers, "orders": orders}
+
+	dt, err := datatable.Query(`
+		SELECT c.id, prenom, o.user_id, num_facture, prix_total
+		FROM customers c
+		INNER JOIN orders o ON c.id = o.user_id
+		WHERE date_achat >= '2013-02-01' OR prix_total > 200
+	`, tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"id", "prenom", "user_id", "num_facture", "prix_total",
+		1, "Aimée", 1, "A00103", 203.14,
+		1, "Aimée", 1, "A00104", 124.00,
+		2, "Esmée", 2, "A00105", 149.45,
+		3, "Marine", 3, "A00106", 235.35,
+	)
+
+	dt, err = datatable.Query(`
+		SELECT c.prenom, count(o.num_facture) AS orders, sum(o.prix_total) AS total
+		FROM customers AS c
+		LEFT JOIN orders AS o ON o.user_id = c.id
+		GROUP BY c.prenom
+		ORDER BY total DESC, prenom
+	`, tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"prenom", "orders", "total",
+		"Aimée", int64(2), 327.14,
+		"Marine", int64(1), 235.35,
+		"Esmée", int64(1), 149.45,
+		"Luc", int64(0), nil,
+	)
+
+	dt, err = datatable.Query("SELECT c.id, o.user_id, o.date_achat FROM customers c RIGHT JOIN orders o ON c.id = o.user_id WHERE c.id IS NULL", tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"id", "user_id", "date_achat",
+		nil, 5, time.Date(2013, time.March, 2, 0, 0, 0, 0, time.UTC),
+	)
+
+	dt, err = datatable.Query("SELECT id FROM customers c OUTER JOIN orders o ON c.id = o.user_id", tables)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, dt.NumRows())
+}
+
+func TestQueryJoinNullKeys(t *testing.T) {
+	a := datatable.New("a")
+	a.AddColumn("k", datatable.Int, datatable.Values(1, nil))
+	a.AddColumn("x", datatable.String, datatable.Values("a1", "a2"))
+	b := datatable.New("b")
+	b.AddColumn("k", datatable.Int, datatable.Values(1, nil))
+	b.AddColumn("y", datatable.String, datatable.Values("b1", "b2"))
+	tables := map[string]*datatable.DataTable{"a": a, "b": b}
+
+	// NULL = NULL is not true
+	dt, err := datatable.Query("SELECT x, y FROM a INNER JOIN b ON a.k = b.k", tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"x", "y",
+		"a1", "b1",
+	)
+
+	dt, err = datatable.Query("SELECT x, y FROM a LEFT JOIN b ON a.k = b.k ORDER BY x", tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"x", "y",
+		"a1", "b1",
+		"a2", nil,
+	)
+
+	dt, err = datatable.Query("SELECT x, y FROM a RIGHT JOIN b ON a.k = b.k ORDER BY y", tables)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"x", "y",
+		"a1", "b1",
+		nil, "b2",
+	)
+
+	dt, err = datatable.Query("SELECT x, y FROM a OUTER JOIN b ON a.k = b.k ORDER BY x, y", tables)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, dt.NumRows())
+}
+
+func TestQueryAggregateNoValues(t *testing.T) {
+	dt := datatable.New("t")
+	dt.AddColumn("g", datatable.String, datatable.Values("a", "b", "b"))
+	dt.AddColumn("v", datatable.Int, datatable.Values(nil, 1, 2))
+	tables := map[string]*datatable.DataTable{"t": dt}
+
+	out, err := datatable.Query("SELECT g, sum(v) AS s, avg(v) AS m, count(v) AS n FROM t GROUP BY g ORDER BY g", tables)
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"g", "s", "m", "n",
+		"a", nil, nil, int64(0),
+		"b", 3.0, 1.5, int64(2),
+	)
+
+	out, err = datatable.Query("SELECT sum(v) AS s FROM t WHERE g = 'c'", tables)
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"s",
+		nil,
+	)
+}
+
+func TestQueryErrors(t *testing.T) {
+	customers, orders := sampleForJoin()
+	tables := map[string]*datatable.DataTable{"customers": customers, "orders": orders}
+
+	for _, sql := range []string{
+		"SELECT * FORM customers",
+		"SELECT * FROM unknown",
+		"SELECT unknown FROM customers",
+		"SELECT * FROM customers c JOIN customers d ON c.id = d.id WHERE id = 1",
+		"SELECT * FROM customers JOIN customers ON id = id",
+		"SELECT * FROM customers c JOIN orders o ON c.id > o.user_id",
+		"SELECT unknown_func(id) FROM customers",
+		"SELECT * FROM customers WHERE sum(id) > 1",
+		"SELECT * FROM customers HAVING id > 1",
+		"SELECT * FROM customers GROUP BY ville",
+		"SELECT ville FROM customers ORDER BY 2",
+		"SELECT DISTINCT ville FROM customers ORDER BY id",
+		"SELECT DISTINCT ville FROM customers ORDER BY upper(nom)",
+	} {
+		dt, err := datatable.Query(sql, tables)
+		assert.Error(t, err, sql)
+		assert.Nil(t, dt, sql)
+	}
+}
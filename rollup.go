@@ -0,0 +1,133 @@
+package datatable
+
+import (
+	"math/bits"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// GroupingColumn is the default name of the grouping level column added by Rollup and Cube.
+// As the SQL GROUPING_ID function, the level is a bit mask where the bit of a key
+// is set when the key is rolled up (ie the row is a subtotal or the grand total).
+// The first key is the most significant bit.
+const GroupingColumn = "grouping"
+
+// GroupingOptions describes the options of Rollup and Cube
+type GroupingOptions struct {
+	Column     string      // name of the grouping level column, GroupingColumn by default
+	TotalLabel interface{} // value of the rolled up key cells, nil by default
+}
+
+// GroupingOption sets grouping options
+type GroupingOption func(opts *GroupingOptions)
+
+// GroupingColumnName sets the name of the grouping level column (default GroupingColumn)
+func GroupingColumnName(name string) GroupingOption {
+	return func(opts *GroupingOptions) {
+		opts.Column = name
+	}
+}
+
+// GroupingTotalLabel sets the value of the rolled up key cells, ie "Total" (default nil).
+// The label is converted to the type of the key column, ie it stays nil in a numeric key column.
+func GroupingTotalLabel(label interface{}) GroupingOption {
+	return func(opts *GroupingOptions) {
+		opts.TotalLabel = label
+	}
+}
+
+func newGroupingOptions(opt ...GroupingOption) GroupingOptions {
+	options := GroupingOptions{Column: GroupingColumn}
+	for _, o := range opt {
+		o(&options)
+	}
+	return options
+}
+
+// Rollup aggregates the datatable with subtotals for each level of keys and a grand total,
+// as the SQL GROUP BY ROLLUP(keys...).
+// The key cells of the super-aggregate rows are nil, see RollupWith to label them.
+func (dt *DataTable) Rollup(keys []GroupBy, aggs ...AggregateBy) (*DataTable, error) {
+	return dt.RollupWith(keys, aggs)
+}
+
+// RollupWith is Rollup with grouping options
+func (dt *DataTable) RollupWith(keys []GroupBy, aggs []AggregateBy, opt ...GroupingOption) (*DataTable, error) {
+	masks := make([]int, 0, len(keys)+1)
+	for i := 0; i <= len(keys); i++ {
+		masks = append(masks, 1<<i-1)
+	}
+	return dt.groupingSets(keys, masks, aggs, newGroupingOptions(opt...))
+}
+
+// Cube aggregates the datatable with subtotals for all combinations of keys and a grand total,
+// as the SQL GROUP BY CUBE(keys...).
+// The key cells of the super-aggregate rows are nil, see CubeWith to label them.
+func (dt *DataTable) Cube(keys []GroupBy, aggs ...AggregateBy) (*DataTable, error) {
+	return dt.CubeWith(keys, aggs)
+}
+
+// CubeWith is Cube with grouping options
+func (dt *DataTable) CubeWith(keys []GroupBy, aggs []AggregateBy, opt ...GroupingOption) (*DataTable, error) {
+	masks := make([]int, 0, 1<<len(keys))
+	for mask := 0; mask < 1<<len(keys); mask++ {
+		masks = append(masks, mask)
+	}
+	sort.SliceStable(masks, func(i, j int) bool {
+		return bits.OnesCount(uint(masks[i])) < bits.OnesCount(uint(masks[j]))
+	})
+	return dt.groupingSets(keys, masks, aggs, newGroupingOptions(opt...))
+}
+
+// groupingSets aggregates the datatable for each grouping level and concats the results
+func (dt *DataTable) groupingSets(keys []GroupBy, masks []int, aggs []AggregateBy, options GroupingOptions) (*DataTable, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoGroupBy
+	}
+	if len(options.Column) == 0 {
+		return nil, ErrNilColumnName
+	}
+
+	n := len(keys)
+	tables := make([]*DataTable, 0, len(masks))
+	label := options.TotalLabel
+
+	for _, mask := range masks {
+		by := make([]GroupBy, n)
+		for i, k := range keys {
+			by[i] = k
+			if mask&(1<<(n-1-i)) != 0 {
+				by[i].Keyer = func(Row) (interface{}, bool) {
+					return label, label != nil
+				}
+			}
+		}
+
+		groups, err := dt.GroupBy(by...)
+		if err != nil {
+			return nil, err
+		}
+		out, err := groups.Aggregate(aggs...)
+		if err != nil {
+			return nil, err
+		}
+
+		if out.ColumnIndex(options.Column) >= 0 {
+			err := errors.Errorf("grouping column '%s' already exists, see GroupingColumnName", options.Column)
+			return nil, errors.Wrap(err, ErrColumnAlreadyExists.Error())
+		}
+		levels := make([]interface{}, out.NumRows())
+		for i := range levels {
+			levels[i] = mask
+		}
+		if err := out.AddColumn(options.Column, Int, Values(levels...)); err != nil {
+			err = errors.Wrapf(err, "can't add column '%s'", options.Column)
+			return nil, errors.Wrap(err, ErrCantAddColumn.Error())
+		}
+
+		tables = append(tables, out)
+	}
+
+	return Concat(tables)
+}
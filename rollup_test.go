@@ -0,0 +1,102 @@
+package datatable_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func keysForRollup() []datatable.GroupBy {
+	return []datatable.GroupBy{
+		{
+			Name: "region",
+			Keyer: func(row datatable.Row) (interface{}, bool) {
+				return row.Get("region"), true
+			},
+		},
+		{
+			Name: "city",
+			Keyer: func(row datatable.Row) (interface{}, bool) {
+				return row.Get("city"), true
+			},
+		},
+	}
+}
+
+func TestRollup(t *testing.T) {
+	dt := sampleForGroups()
+
+	out, err := dt.Rollup(keysForRollup(), datatable.AggregateBy{Type: datatable.Sum, Field: "amount", As: "total"})
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"region", "city", "total", datatable.GroupingColumn,
+		"north", "lille", 10.0, 0,
+		"north", "amiens", 20.0, 0,
+		"south", "nice", 20.0, 0,
+		"south", "marseille", 30.0, 0,
+		"east", "metz", 8.0, 0,
+		"north", nil, 30.0, 1,
+		"south", nil, 50.0, 1,
+		"east", nil, 8.0, 1,
+		nil, nil, 88.0, 3,
+	)
+
+	_, err = dt.Rollup(nil, datatable.AggregateBy{Type: datatable.Sum, Field: "amount"})
+	assert.Error(t, err)
+}
+
+func TestRollupWith(t *testing.T) {
+	dt := sampleForGroups()
+	aggs := []datatable.AggregateBy{{Type: datatable.Sum, Field: "amount", As: "total"}}
+
+	out, err := dt.RollupWith(keysForRollup(), aggs,
+		datatable.GroupingColumnName("level"),
+		datatable.GroupingTotalLabel("Total"),
+	)
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"region", "city", "total", "level",
+		"north", "lille", 10.0, 0,
+		"north", "amiens", 20.0, 0,
+		"south", "nice", 20.0, 0,
+		"south", "marseille", 30.0, 0,
+		"east", "metz", 8.0, 0,
+		"north", "Total", 30.0, 1,
+		"south", "Total", 50.0, 1,
+		"east", "Total", 8.0, 1,
+		"Total", "Total", 88.0, 3,
+	)
+
+	// the grouping column clashes with an output column
+	aggs = []datatable.AggregateBy{{Type: datatable.Sum, Field: "amount", As: datatable.GroupingColumn}}
+	_, err = dt.Rollup(keysForRollup(), aggs...)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), datatable.ErrColumnAlreadyExists.Error())
+	_, err = dt.CubeWith(keysForRollup(), aggs, datatable.GroupingColumnName("level"))
+	assert.NoError(t, err)
+}
+
+func TestCube(t *testing.T) {
+	dt := sampleForGroups()
+
+	out, err := dt.Cube(keysForRollup(), datatable.AggregateBy{Type: datatable.Count, Field: "amount", As: "count"})
+	assert.NoError(t, err)
+	checkTable(t, out,
+		"region", "city", "count", datatable.GroupingColumn,
+		"north", "lille", int64(1), 0,
+		"north", "amiens", int64(1), 0,
+		"south", "nice", int64(2), 0,
+		"south", "marseille", int64(1), 0,
+		"east", "metz", int64(1), 0,
+		"north", nil, int64(2), 1,
+		"south", nil, int64(3), 1,
+		"east", nil, int64(1), 1,
+		nil, "lille", int64(1), 2,
+		nil, "amiens", int64(1), 2,
+		nil, "nice", int64(2), 2,
+		nil, "marseille", int64(1), 2,
+		nil, "metz", int64(1), 2,
+		nil, nil, int64(6), 3,
+	)
+}
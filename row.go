@@ -1,11 +1,6 @@
 package datatable
 
-import (
-	"bytes"
-	"encoding/gob"
-
-	"github.com/cespare/xxhash"
-)
+import "sort"
 
 // Row contains a row relative to columns
 type Row map[string]interface{}
@@ -28,10 +23,10 @@ func (r Row) Get(k string) interface{} {
 // Hash computes the hash code from this datarow
 // can be used to filter the table (distinct rows)
 func (r Row) Hash() uint64 {
-	buff := new(bytes.Buffer)
-	enc := gob.NewEncoder(buff)
-	for _, v := range r {
-		enc.Encode(v)
+	keys := make([]string, 0, len(r))
+	for k := range r {
+		keys = append(keys, k)
 	}
-	return xxhash.Sum64(buff.Bytes())
+	sort.Strings(keys)
+	return hasher.Row(r, keys)
 }
@@ -1,5 +1,11 @@
 package datatable
 
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
 // Subset selects rows at index with size
 func (t *DataTable) Subset(at, size int) *DataTable {
 	cpy := t.EmptyCopy()
@@ -25,3 +31,179 @@ func (t *DataTable) Head(size int) *DataTable {
 func (t *DataTable) Tail(size int) *DataTable {
 	return t.Subset(t.nrows-size, size)
 }
+
+// pick selects the rows at index to create a new datatable
+func (t *DataTable) pick(at ...int) *DataTable {
+	cpy := t.EmptyCopy()
+	cpy.nrows = len(at)
+	for i, col := range t.cols {
+		cpy.cols[i].serie = col.serie.Pick(at...)
+	}
+	return cpy
+}
+
+// project creates a new datatable with the columns in this order.
+// A computed column is materialized if one of its dependencies is not projected.
+func (t *DataTable) project(cols ...string) (*DataTable, error) {
+	if err := t.checkColumns(cols...); err != nil {
+		return nil, err
+	}
+	if err := t.evaluateExpressions(); err != nil {
+		return nil, err
+	}
+
+	kept := make(map[string]bool, len(cols))
+	for _, name := range cols {
+		kept[name] = true
+	}
+
+	ccols := make([]*column, 0, len(cols))
+	for _, name := range cols {
+		col := t.cols[t.ColumnIndex(name)]
+		ccpy := col.copy()
+		for _, dep := range col.deps {
+			if !kept[dep] {
+				ccpy = col.plainCopy()
+				ccpy.serie = col.serie.Copy()
+				break
+			}
+		}
+		ccols = append(ccols, ccpy)
+	}
+
+	cpy := New(t.name)
+	if err := cpy.addColumnsInOrder(ccols); err != nil {
+		return nil, err
+	}
+	cpy.clean()
+	return cpy, nil
+}
+
+// addColumnsInOrder adds the columns after their dependencies, then keeps the order of {cols}
+func (t *DataTable) addColumnsInOrder(cols []*column) error {
+	n := len(t.cols)
+	pending := cols
+	for len(pending) > 0 {
+		next := pending[:0:0]
+		for _, col := range pending {
+			ready := true
+			for _, dep := range col.deps {
+				if dep != col.name && t.ColumnIndex(dep) < 0 && hasColumn(pending, dep) {
+					ready = false
+					break
+				}
+			}
+			if !ready {
+				next = append(next, col)
+				continue
+			}
+			if err := t.addColumn(col); err != nil {
+				return err
+			}
+		}
+		if len(next) == len(pending) {
+			// a cycle: reported by addColumn
+			return t.addColumn(next[0])
+		}
+		pending = next
+	}
+
+	ordered := make([]*column, n, len(t.cols))
+	copy(ordered, t.cols[:n])
+	for _, col := range cols {
+		ordered = append(ordered, t.cols[t.ColumnIndex(col.name)])
+	}
+	t.cols = ordered
+	return nil
+}
+
+// hasColumn checks if a column is named {name}
+func hasColumn(cols []*column, name string) bool {
+	for _, col := range cols {
+		if col.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Select creates a new datatable with exactly the columns in this order.
+// A computed column keeps its expression if its dependencies are selected,
+// otherwise its values are copied.
+func (t *DataTable) Select(cols ...string) (*DataTable, error) {
+	return t.project(cols...)
+}
+
+// Drop creates a new datatable without the columns
+func (t *DataTable) Drop(cols ...string) (*DataTable, error) {
+	if err := t.checkColumns(cols...); err != nil {
+		return nil, err
+	}
+
+	dropped := make(map[string]bool, len(cols))
+	for _, name := range cols {
+		dropped[name] = true
+	}
+
+	return t.selectColumns(func(col *column) bool {
+		return !dropped[col.name]
+	})
+}
+
+// Reorder creates a new datatable with the columns first, in this order.
+// The other columns follow in their initial order.
+func (t *DataTable) Reorder(cols ...string) (*DataTable, error) {
+	if err := t.checkColumns(cols...); err != nil {
+		return nil, err
+	}
+
+	first := make(map[string]bool, len(cols))
+	for _, name := range cols {
+		first[name] = true
+	}
+
+	ordered := make([]string, 0, len(t.cols))
+	ordered = append(ordered, cols...)
+	for _, col := range t.cols {
+		if !first[col.name] {
+			ordered = append(ordered, col.name)
+		}
+	}
+	return t.project(ordered...)
+}
+
+// SelectMatch creates a new datatable with the columns matching the regular expression
+func (t *DataTable) SelectMatch(pattern string) (*DataTable, error) {
+	rg, err := regexp.Compile(pattern)
+	if err != nil {
+		err = errors.Wrapf(err, "select pattern '%s'", pattern)
+		return nil, errors.Wrap(err, ErrInvalidPattern.Error())
+	}
+
+	return t.selectColumns(func(col *column) bool {
+		return rg.MatchString(col.name)
+	})
+}
+
+// SelectTypes creates a new datatable with the columns of these types
+func (t *DataTable) SelectTypes(types ...ColumnType) (*DataTable, error) {
+	selected := make(map[ColumnType]bool, len(types))
+	for _, typ := range types {
+		selected[typ] = true
+	}
+
+	return t.selectColumns(func(col *column) bool {
+		return selected[col.typ]
+	})
+}
+
+// selectColumns projects the columns matching the predicate
+func (t *DataTable) selectColumns(predicate func(col *column) bool) (*DataTable, error) {
+	cols := make([]string, 0, len(t.cols))
+	for _, col := range t.cols {
+		if predicate(col) {
+			cols = append(cols, col.name)
+		}
+	}
+	return t.project(cols...)
+}
@@ -0,0 +1,122 @@
+package datatable_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func sampleForSelect() *datatable.DataTable {
+	tb := datatable.New("test")
+	tb.AddColumn("champ", datatable.String, datatable.Values("Malzahar", "Xerath", "Teemo"))
+	tb.AddColumn("champion", datatable.String, datatable.Expr("upper(`champ`)"))
+	tb.AddColumn("win", datatable.Int, datatable.Values(10, 20, 666))
+	tb.AddColumn("loose", datatable.Int, datatable.Values(6, 5, 666))
+	tb.AddColumn("winRate", datatable.Float64, datatable.Expr("(`win` * 100 / (`win` + `loose`))"))
+	return tb
+}
+
+func TestSelect(t *testing.T) {
+	tb := sampleForSelect()
+
+	dt, err := tb.Select("winRate", "win", "loose")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"winRate", "win", "loose",
+		62.5, 10, 6,
+		80.0, 20, 5,
+		50.0, 666, 666,
+	)
+	assert.True(t, dt.Column("winRate").IsComputed())
+
+	// the expression is kept consistent with its dependencies
+	dt.Update(0, datatable.Row{"win": 30, "loose": 10})
+	checkTable(t, dt,
+		"winRate", "win", "loose",
+		75.0, 30, 10,
+		80.0, 20, 5,
+		50.0, 666, 666,
+	)
+
+	// dependency not selected => values
+	dt, err = tb.Select("champion", "winRate")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"champion", "winRate",
+		"MALZAHAR", 62.5,
+		"XERATH", 80.0,
+		"TEEMO", 50.0,
+	)
+	assert.False(t, dt.Column("champion").IsComputed())
+	assert.False(t, dt.Column("winRate").IsComputed())
+
+	_, err = tb.Select("champ", "unknown")
+	assert.Error(t, err)
+
+	// the source table is not modified
+	assert.Equal(t, []string{"champ", "champion", "win", "loose", "winRate"}, tb.Columns())
+}
+
+func TestDrop(t *testing.T) {
+	tb := sampleForSelect()
+
+	dt, err := tb.Drop("champion", "loose")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"champ", "win", "winRate",
+		"Malzahar", 10, 62.5,
+		"Xerath", 20, 80.0,
+		"Teemo", 666, 50.0,
+	)
+
+	_, err = tb.Drop("unknown")
+	assert.Error(t, err)
+}
+
+func TestReorder(t *testing.T) {
+	tb := sampleForSelect()
+
+	dt, err := tb.Reorder("winRate", "champ")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"winRate", "champ", "champion", "win", "loose",
+		62.5, "Malzahar", "MALZAHAR", 10, 6,
+		80.0, "Xerath", "XERATH", 20, 5,
+		50.0, "Teemo", "TEEMO", 666, 666,
+	)
+	assert.True(t, dt.Column("winRate").IsComputed())
+
+	_, err = tb.Reorder("unknown")
+	assert.Error(t, err)
+}
+
+func TestSelectMatchAndTypes(t *testing.T) {
+	tb := sampleForSelect()
+
+	dt, err := tb.SelectMatch("^champ")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"champ", "champion",
+		"Malzahar", "MALZAHAR",
+		"Xerath", "XERATH",
+		"Teemo", "TEEMO",
+	)
+	assert.True(t, dt.Column("champion").IsComputed())
+
+	_, err = tb.SelectMatch("[")
+	assert.Error(t, err)
+
+	dt, err = tb.SelectTypes(datatable.Int, datatable.Float64)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"win", "loose", "winRate",
+		10, 6, 62.5,
+		20, 5, 80.0,
+		666, 666, 50.0,
+	)
+
+	dt, err = tb.SelectTypes(datatable.Bool)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, dt.NumCols())
+}
@@ -0,0 +1,63 @@
+package serie
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/datasweet/cast"
+)
+
+// CompareValues compares 2 non-nil values, returns Lt, Eq or Gt
+// Numbers are compared together: integers as int64, others as float64.
+// Other values must have the same type: string, bool or time.Time.
+// Returns false if the values can't be compared.
+func CompareValues(x, y interface{}) (int, bool) {
+	if i, ok := x.(Interfacer); ok {
+		x = i.Interface()
+	}
+	if i, ok := y.(Interfacer); ok {
+		y = i.Interface()
+	}
+
+	kx, ky := reflect.ValueOf(x).Kind(), reflect.ValueOf(y).Kind()
+	switch {
+	case isIntegerKind(kx) && isIntegerKind(ky):
+		xi, _ := cast.AsInt64(x)
+		yi, _ := cast.AsInt64(y)
+		return compareInt64(xi, yi), true
+	case isNumberKind(kx) && isNumberKind(ky):
+		xf, _ := cast.AsFloat64(x)
+		yf, _ := cast.AsFloat64(y)
+		return compareFloat64(xf, yf), true
+	}
+
+	switch vx := x.(type) {
+	case string:
+		if vy, ok := y.(string); ok {
+			return strings.Compare(vx, vy), true
+		}
+	case bool:
+		if vy, ok := y.(bool); ok {
+			return compareBool(vx, vy), true
+		}
+	case time.Time:
+		if vy, ok := y.(time.Time); ok {
+			return compareTime(vx, vy), true
+		}
+	}
+	return 0, false
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	return isIntegerKind(k) || k == reflect.Float32 || k == reflect.Float64
+}
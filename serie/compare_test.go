@@ -0,0 +1,35 @@
+package serie_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable/serie"
+)
+
+func TestCompareValues(t *testing.T) {
+	now := time.Now()
+	for _, tt := range []struct {
+		x, y interface{}
+		cmp  int
+	}{
+		{1, int64(2), serie.Lt},
+		{uint8(3), 2, serie.Gt},
+		{int64(1<<62 + 1), int64(1 << 62), serie.Gt},
+		{1.5, 1, serie.Gt},
+		{float32(2), 2, serie.Eq},
+		{"a", "b", serie.Lt},
+		{true, false, serie.Gt},
+		{now, now.Add(time.Second), serie.Lt},
+	} {
+		cmp, ok := serie.CompareValues(tt.x, tt.y)
+		assert.True(t, ok, "%v %v", tt.x, tt.y)
+		assert.Equal(t, tt.cmp, cmp, "%v %v", tt.x, tt.y)
+	}
+
+	_, ok := serie.CompareValues("1", 1)
+	assert.False(t, ok)
+	_, ok = serie.CompareValues(true, "true")
+	assert.False(t, ok)
+}
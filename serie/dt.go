@@ -0,0 +1,232 @@
+package serie
+
+import (
+	"time"
+
+	"github.com/datasweet/cast"
+	"github.com/pkg/errors"
+)
+
+// TimePart defines a part of a time
+type TimePart uint8
+
+const (
+	Year      TimePart = iota
+	Quarter            // 1 to 4
+	Month              // 1 to 12
+	Week               // ISO 8601 week, 1 to 53
+	Day                // day of month
+	DayOfYear          // 1 to 366
+	Weekday            // 0 (sunday) to 6
+	Hour
+	Minute
+	Second
+)
+
+// Of returns the part of the time
+func (p TimePart) Of(tm time.Time) int {
+	switch p {
+	case Year:
+		return tm.Year()
+	case Quarter:
+		return (int(tm.Month())-1)/3 + 1
+	case Month:
+		return int(tm.Month())
+	case Week:
+		_, week := tm.ISOWeek()
+		return week
+	case Day:
+		return tm.Day()
+	case DayOfYear:
+		return tm.YearDay()
+	case Weekday:
+		return int(tm.Weekday())
+	case Hour:
+		return tm.Hour()
+	case Minute:
+		return tm.Minute()
+	case Second:
+		return tm.Second()
+	}
+	return 0
+}
+
+// Truncate returns the beginning of the period of the time, in the time location.
+// A week begins on monday, DayOfYear and Weekday truncate to the day.
+func (p TimePart) Truncate(tm time.Time) time.Time {
+	y, m, d := tm.Date()
+	loc := tm.Location()
+	switch p {
+	case Year:
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, loc)
+	case Quarter:
+		return time.Date(y, time.Month((int(m)-1)/3*3+1), 1, 0, 0, 0, 0, loc)
+	case Month:
+		return time.Date(y, m, 1, 0, 0, 0, 0, loc)
+	case Week:
+		offset := (int(tm.Weekday()) + 6) % 7 // days since monday
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, loc)
+	case Day, DayOfYear, Weekday:
+		return time.Date(y, m, d, 0, 0, 0, 0, loc)
+	case Hour:
+		return time.Date(y, m, d, tm.Hour(), 0, 0, 0, loc)
+	case Minute:
+		return time.Date(y, m, d, tm.Hour(), tm.Minute(), 0, 0, loc)
+	case Second:
+		return time.Date(y, m, d, tm.Hour(), tm.Minute(), tm.Second(), 0, loc)
+	}
+	return tm
+}
+
+// TimeOps provides time operations on a serie, like pandas .dt
+// Each operation returns a new serie: a nil value stays nil.
+type TimeOps struct {
+	serie   Serie
+	formats []string
+}
+
+// Dt returns the time operations of a serie
+// Made for Time and TimeN series, the values of other series are parsed with the formats.
+func Dt(s Serie, formats ...string) *TimeOps {
+	return &TimeOps{serie: s, formats: formats}
+}
+
+// each calls {fn} on each time, and appends the result to {out}
+func (o *TimeOps) each(out Serie, fn func(tm time.Time) interface{}) Serie {
+	values := make([]interface{}, 0, o.serie.Len())
+	for i := 0; i < o.serie.Len(); i++ {
+		if tm, ok := o.at(i); ok {
+			values = append(values, fn(tm))
+		} else {
+			values = append(values, nil)
+		}
+	}
+	out.Append(values...)
+	return out
+}
+
+// at returns the time at index
+func (o *TimeOps) at(i int) (time.Time, bool) {
+	switch v := o.serie.Get(i).(type) {
+	case nil:
+		return time.Time{}, false
+	case time.Time:
+		return v, true
+	default:
+		return cast.AsTime(v, o.formats...)
+	}
+}
+
+// transform returns a TimeN serie
+// The times are appended as NullTime to keep their location.
+func (o *TimeOps) transform(fn func(tm time.Time) time.Time) Serie {
+	return o.each(TimeN(), func(tm time.Time) interface{} {
+		return NullTime{Time: fn(tm), Valid: true}
+	})
+}
+
+// Part returns the part of the times
+func (o *TimeOps) Part(p TimePart) Serie {
+	return o.each(IntN(), func(tm time.Time) interface{} {
+		return p.Of(tm)
+	})
+}
+
+// Year returns the year of the times
+func (o *TimeOps) Year() Serie {
+	return o.Part(Year)
+}
+
+// Quarter returns the quarter of the times, 1 to 4
+func (o *TimeOps) Quarter() Serie {
+	return o.Part(Quarter)
+}
+
+// Month returns the month of the times, 1 to 12
+func (o *TimeOps) Month() Serie {
+	return o.Part(Month)
+}
+
+// Week returns the ISO 8601 week of the times
+func (o *TimeOps) Week() Serie {
+	return o.Part(Week)
+}
+
+// Day returns the day of month of the times
+func (o *TimeOps) Day() Serie {
+	return o.Part(Day)
+}
+
+// Weekday returns the day of week of the times, 0 (sunday) to 6
+func (o *TimeOps) Weekday() Serie {
+	return o.Part(Weekday)
+}
+
+// Hour returns the hour of the times
+func (o *TimeOps) Hour() Serie {
+	return o.Part(Hour)
+}
+
+// Truncate returns the beginning of the period of the times, see TimePart.Truncate
+func (o *TimeOps) Truncate(p TimePart) Serie {
+	return o.transform(p.Truncate)
+}
+
+// In returns the times in the location
+func (o *TimeOps) In(loc *time.Location) Serie {
+	return o.transform(func(tm time.Time) time.Time {
+		return tm.In(loc)
+	})
+}
+
+// Add returns the times plus the duration, which can be negative
+func (o *TimeOps) Add(d time.Duration) Serie {
+	return o.transform(func(tm time.Time) time.Time {
+		return tm.Add(d)
+	})
+}
+
+// AddDate returns the times plus the years, months and days, which can be negative
+func (o *TimeOps) AddDate(years, months, days int) Serie {
+	return o.transform(func(tm time.Time) time.Time {
+		return tm.AddDate(years, months, days)
+	})
+}
+
+// Diff returns the difference between the times and {other} in {unit}, ie time.Hour
+// {other} is a serie or a time. The result is nil if a time is nil.
+func (o *TimeOps) Diff(other interface{}, unit time.Duration) (Serie, error) {
+	var at func(i int) (time.Time, bool)
+	if s, ok := other.(Serie); ok {
+		if s.Len() != o.serie.Len() {
+			err := errors.Errorf("len %d and %d", o.serie.Len(), s.Len())
+			return nil, errors.Wrap(err, ErrLengthMismatch.Error())
+		}
+		at = Dt(s, o.formats...).at
+	} else {
+		tm, ok := cast.AsTime(other, o.formats...)
+		at = func(int) (time.Time, bool) { return tm, ok && other != nil }
+	}
+	if unit <= 0 {
+		unit = time.Nanosecond
+	}
+
+	out := Float64N()
+	values := make([]interface{}, o.serie.Len())
+	for i := range values {
+		a, oka := o.at(i)
+		b, okb := at(i)
+		if oka && okb {
+			values[i] = float64(a.Sub(b)) / float64(unit)
+		}
+	}
+	out.Append(values...)
+	return out, nil
+}
+
+// Format returns the times formatted with the layout
+func (o *TimeOps) Format(layout string) Serie {
+	return o.each(StringN(), func(tm time.Time) interface{} {
+		return tm.Format(layout)
+	})
+}
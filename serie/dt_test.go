@@ -0,0 +1,59 @@
+package serie_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable/serie"
+)
+
+func TestTimeOps(t *testing.T) {
+	s := serie.TimeN()
+	s.Append("2020-02-29T13:45:10Z", nil, "2021-01-03T08:00:00Z")
+	dt := serie.Dt(s)
+
+	assertSerieEq(t, dt.Year(), 2020, nil, 2021)
+	assertSerieEq(t, dt.Quarter(), 1, nil, 1)
+	assertSerieEq(t, dt.Month(), 2, nil, 1)
+	assertSerieEq(t, dt.Week(), 9, nil, 53)
+	assertSerieEq(t, dt.Day(), 29, nil, 3)
+	assertSerieEq(t, dt.Weekday(), 6, nil, 0)
+	assertSerieEq(t, dt.Hour(), 13, nil, 8)
+	assertSerieEq(t, dt.Part(serie.DayOfYear), 60, nil, 3)
+
+	assertSerieEq(t, dt.Truncate(serie.Day),
+		time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC), nil, time.Date(2021, time.January, 3, 0, 0, 0, 0, time.UTC))
+	assertSerieEq(t, dt.Truncate(serie.Week),
+		time.Date(2020, time.February, 24, 0, 0, 0, 0, time.UTC), nil, time.Date(2020, time.December, 28, 0, 0, 0, 0, time.UTC))
+	assertSerieEq(t, dt.Truncate(serie.Month),
+		time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC), nil, time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	assertSerieEq(t, dt.Add(-time.Hour),
+		time.Date(2020, time.February, 29, 12, 45, 10, 0, time.UTC), nil, time.Date(2021, time.January, 3, 7, 0, 0, 0, time.UTC))
+	assertSerieEq(t, dt.AddDate(0, 1, 0),
+		time.Date(2020, time.March, 29, 13, 45, 10, 0, time.UTC), nil, time.Date(2021, time.February, 3, 8, 0, 0, 0, time.UTC))
+
+	assertSerieEq(t, dt.Format("2006-01-02"), "2020-02-29", nil, "2021-01-03")
+
+	// location is kept
+	loc := time.FixedZone("UTC+2", 2*3600)
+	in := dt.In(loc)
+	assert.Equal(t, loc, in.Get(0).(time.Time).Location())
+	assertSerieEq(t, serie.Dt(in).Hour(), 15, nil, 10)
+
+	diff, err := dt.Diff("2020-02-28T13:45:10Z", 24*time.Hour)
+	assert.NoError(t, err)
+	days := time.Date(2021, time.January, 3, 8, 0, 0, 0, time.UTC).Sub(time.Date(2020, time.February, 28, 13, 45, 10, 0, time.UTC)).Hours() / 24
+	assertSerieEq(t, diff, 1.0, nil, days)
+
+	diff, err = dt.Diff(dt.Add(time.Hour), time.Minute)
+	assert.NoError(t, err)
+	assertSerieEq(t, diff, -60.0, nil, -60.0)
+
+	_, err = dt.Diff(serie.TimeN(), time.Hour)
+	assert.Error(t, err)
+
+	// parsed strings
+	assertSerieEq(t, serie.Dt(serie.String("23/01/2013"), "02/01/2006").Year(), 2013)
+}
@@ -13,3 +13,22 @@ var (
 	ErrShrinkSizeMustBeLesserThanLen   = errors.New("shrink: size must be < len")
 	ErrConcatTypeMismatch              = errors.New("concat: type mismatch")
 )
+
+// Errors in ops.go
+var (
+	ErrLengthMismatch = errors.New("length mismatch")
+	ErrNotNumeric     = errors.New("not numeric")
+	ErrNotComparable  = errors.New("not comparable")
+	ErrNotBool        = errors.New("not bool")
+)
+
+// Errors in map.go
+var (
+	ErrNilFunction  = errors.New("nil function")
+	ErrTypeMismatch = errors.New("type mismatch")
+)
+
+// Errors in str.go
+var (
+	ErrInvalidPattern = errors.New("invalid pattern")
+)
@@ -0,0 +1,57 @@
+package serie
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Map creates a new serie with the result of {fn} on each value.
+// The new serie is an empty copy of {out}, or of the serie if {out} is nil:
+// the results are converted to its type.
+func Map(s Serie, fn func(v interface{}) interface{}, out Serie) (Serie, error) {
+	if fn == nil {
+		return nil, ErrNilFunction
+	}
+	if out == nil {
+		out = s
+	}
+
+	values := make([]interface{}, 0, s.Len())
+	for it := s.Iterator(); it.Next(); {
+		values = append(values, fn(it.Current()))
+	}
+
+	cpy := out.EmptyCopy()
+	cpy.Append(values...)
+	return cpy, nil
+}
+
+// MapOf creates a new serie with the result of the typed {fn} on each value.
+// The new serie is an empty copy of {out}, or of the serie if {out} is nil.
+// A nil value stays nil, the other values must be of type T.
+func MapOf[T, U any](s Serie, fn func(v T) U, out Serie) (Serie, error) {
+	if fn == nil {
+		return nil, ErrNilFunction
+	}
+	if out == nil {
+		out = s
+	}
+
+	values := make([]interface{}, 0, s.Len())
+	for it := s.Iterator(); it.Next(); {
+		v := it.Current()
+		if v == nil {
+			values = append(values, nil)
+			continue
+		}
+		tv, ok := v.(T)
+		if !ok {
+			err := errors.Errorf("value %v at index %d is %T, not %T", v, len(values), v, tv)
+			return nil, errors.Wrap(err, ErrTypeMismatch.Error())
+		}
+		values = append(values, fn(tv))
+	}
+
+	cpy := out.EmptyCopy()
+	cpy.Append(values...)
+	return cpy, nil
+}
@@ -0,0 +1,50 @@
+package serie_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable/serie"
+)
+
+func TestMap(t *testing.T) {
+	s := serie.IntN(1, 2, nil)
+
+	m, err := serie.Map(s, func(v interface{}) interface{} {
+		if v == nil {
+			return 0
+		}
+		return v.(int) * 10
+	}, nil)
+	assert.NoError(t, err)
+	assertSerieEq(t, m, 10, 20, 0)
+
+	m, err = serie.Map(s, func(v interface{}) interface{} {
+		return v
+	}, serie.StringN())
+	assert.NoError(t, err)
+	assertSerieEq(t, m, "1", "2", nil)
+	assertSerieEq(t, s, 1, 2, nil)
+
+	_, err = serie.Map(s, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestMapOf(t *testing.T) {
+	s := serie.StringN("teemo", nil, "ahri")
+
+	m, err := serie.MapOf(s, strings.ToUpper, nil)
+	assert.NoError(t, err)
+	assertSerieEq(t, m, "TEEMO", nil, "AHRI")
+
+	m, err = serie.MapOf(s, func(v string) int { return len(v) }, serie.IntN())
+	assert.NoError(t, err)
+	assertSerieEq(t, m, 5, nil, 4)
+
+	_, err = serie.MapOf(s, func(v int) int { return v }, nil)
+	assert.Error(t, err)
+
+	_, err = serie.MapOf[string, string](s, nil, nil)
+	assert.Error(t, err)
+}
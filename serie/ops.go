@@ -0,0 +1,379 @@
+package serie
+
+import (
+	"math"
+	"reflect"
+
+	"github.com/datasweet/cast"
+	"github.com/pkg/errors"
+)
+
+// Element-wise operations between series, or between a serie and a scalar.
+// A nil value gives a nil result, so the resulting series are nullable.
+//
+// Numeric types are promoted:
+//   - same types keep their type,
+//   - mixed integers give int64,
+//   - a float gives float64, or float32 if both are float32.
+//
+// A scalar adopts the type of the serie if both are integers or both are floats.
+
+// numKind is the numeric type of an operand
+type numKind uint8
+
+const (
+	notNumeric numKind = iota
+	kindInt
+	kindInt32
+	kindInt64
+	kindFloat32
+	kindFloat64
+)
+
+var numKinds = map[reflect.Type]numKind{
+	reflect.TypeOf(int(0)):        kindInt,
+	reflect.TypeOf(NullInt{}):     kindInt,
+	reflect.TypeOf(int32(0)):      kindInt32,
+	reflect.TypeOf(NullInt32{}):   kindInt32,
+	reflect.TypeOf(int64(0)):      kindInt64,
+	reflect.TypeOf(NullInt64{}):   kindInt64,
+	reflect.TypeOf(float32(0)):    kindFloat32,
+	reflect.TypeOf(NullFloat32{}): kindFloat32,
+	reflect.TypeOf(float64(0)):    kindFloat64,
+	reflect.TypeOf(NullFloat64{}): kindFloat64,
+}
+
+func (k numKind) isInteger() bool {
+	return k == kindInt || k == kindInt32 || k == kindInt64
+}
+
+// newSerie creates a nullable serie of the kind
+func (k numKind) newSerie() Serie {
+	switch k {
+	case kindInt:
+		return IntN()
+	case kindInt32:
+		return Int32N()
+	case kindInt64:
+		return Int64N()
+	case kindFloat32:
+		return Float32N()
+	default:
+		return Float64N()
+	}
+}
+
+// operand is a serie, or a scalar broadcasted to all rows
+type operand struct {
+	serie Serie
+	value interface{}
+}
+
+func newOperand(v interface{}) operand {
+	if s, ok := v.(Serie); ok {
+		return operand{serie: s}
+	}
+	if i, ok := v.(Interfacer); ok {
+		v = i.Interface()
+	}
+	return operand{value: v}
+}
+
+func (o operand) isScalar() bool {
+	return o.serie == nil
+}
+
+func (o operand) at(i int) interface{} {
+	if o.serie == nil {
+		return o.value
+	}
+	return o.serie.Get(i)
+}
+
+func (o operand) kind() numKind {
+	if o.serie != nil {
+		return numKinds[o.serie.Type()]
+	}
+	switch reflect.ValueOf(o.value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return kindInt
+	case reflect.Int32:
+		return kindInt32
+	case reflect.Int64, reflect.Uint32, reflect.Uint64:
+		return kindInt64
+	case reflect.Float32:
+		return kindFloat32
+	case reflect.Float64:
+		return kindFloat64
+	case reflect.Invalid:
+		// nil scalar: adopts the other kind
+		return kindInt
+	}
+	return notNumeric
+}
+
+// size returns the len of the result
+func size(ops ...operand) (int, error) {
+	n := -1
+	for _, o := range ops {
+		if o.isScalar() {
+			continue
+		}
+		if n >= 0 && o.serie.Len() != n {
+			err := errors.Errorf("len %d and %d", n, o.serie.Len())
+			return 0, errors.Wrap(err, ErrLengthMismatch.Error())
+		}
+		n = o.serie.Len()
+	}
+	if n < 0 {
+		n = 1 // only scalars
+	}
+	return n, nil
+}
+
+// promote returns the kind of the result
+func promote(a, b operand) (numKind, error) {
+	ka, kb := a.kind(), b.kind()
+	if ka == notNumeric || kb == notNumeric {
+		return notNumeric, ErrNotNumeric
+	}
+	switch {
+	case ka == kb:
+		return ka, nil
+	case a.isScalar() && !b.isScalar() && (ka.isInteger() == kb.isInteger()):
+		return kb, nil
+	case b.isScalar() && !a.isScalar() && (ka.isInteger() == kb.isInteger()):
+		return ka, nil
+	case ka.isInteger() && kb.isInteger():
+		return kindInt64, nil
+	default:
+		return kindFloat64, nil
+	}
+}
+
+// arithmetic computes an element-wise numeric operation
+// the operation returns false if the result is nil
+func arithmetic(a, b interface{}, intOp func(x, y int64) (int64, bool), floatOp func(x, y float64) (float64, bool)) (Serie, error) {
+	oa, ob := newOperand(a), newOperand(b)
+	n, err := size(oa, ob)
+	if err != nil {
+		return nil, err
+	}
+	kind, err := promote(oa, ob)
+	if err != nil {
+		return nil, err
+	}
+
+	out := kind.newSerie()
+	values := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		x, y := oa.at(i), ob.at(i)
+		if x == nil || y == nil {
+			continue
+		}
+		if kind.isInteger() {
+			xi, _ := cast.AsInt64(x)
+			yi, _ := cast.AsInt64(y)
+			if v, ok := intOp(xi, yi); ok {
+				values[i] = v
+			}
+			continue
+		}
+		xf, _ := cast.AsFloat64(x)
+		yf, _ := cast.AsFloat64(y)
+		if v, ok := floatOp(xf, yf); ok {
+			values[i] = v
+		}
+	}
+	out.Append(values...)
+	return out, nil
+}
+
+// Add returns a + b
+func Add(a, b interface{}) (Serie, error) {
+	return arithmetic(a, b,
+		func(x, y int64) (int64, bool) { return x + y, true },
+		func(x, y float64) (float64, bool) { return x + y, true },
+	)
+}
+
+// Sub returns a - b
+func Sub(a, b interface{}) (Serie, error) {
+	return arithmetic(a, b,
+		func(x, y int64) (int64, bool) { return x - y, true },
+		func(x, y float64) (float64, bool) { return x - y, true },
+	)
+}
+
+// Mul returns a * b
+func Mul(a, b interface{}) (Serie, error) {
+	return arithmetic(a, b,
+		func(x, y int64) (int64, bool) { return x * y, true },
+		func(x, y float64) (float64, bool) { return x * y, true },
+	)
+}
+
+// Div returns a / b
+// The division of integers is an integer division, a division by zero gives nil.
+func Div(a, b interface{}) (Serie, error) {
+	return arithmetic(a, b,
+		func(x, y int64) (int64, bool) {
+			if y == 0 {
+				return 0, false
+			}
+			return x / y, true
+		},
+		func(x, y float64) (float64, bool) {
+			if y == 0 {
+				return 0, false
+			}
+			return x / y, true
+		},
+	)
+}
+
+// Mod returns a % b
+// A modulo by zero gives nil.
+func Mod(a, b interface{}) (Serie, error) {
+	return arithmetic(a, b,
+		func(x, y int64) (int64, bool) {
+			if y == 0 {
+				return 0, false
+			}
+			return x % y, true
+		},
+		func(x, y float64) (float64, bool) {
+			if y == 0 {
+				return 0, false
+			}
+			return math.Mod(x, y), true
+		},
+	)
+}
+
+// comparison computes an element-wise comparison
+func comparison(a, b interface{}, test func(cmp int) bool) (Serie, error) {
+	oa, ob := newOperand(a), newOperand(b)
+	n, err := size(oa, ob)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		x, y := oa.at(i), ob.at(i)
+		if x == nil || y == nil {
+			continue
+		}
+		cmp, ok := CompareValues(x, y)
+		if !ok {
+			err := errors.Errorf("can't compare %T with %T at index %d", x, y, i)
+			return nil, errors.Wrap(err, ErrNotComparable.Error())
+		}
+		values[i] = test(cmp)
+	}
+	return BoolN(values...), nil
+}
+
+// Equal returns a == b
+func Equal(a, b interface{}) (Serie, error) {
+	return comparison(a, b, func(cmp int) bool { return cmp == Eq })
+}
+
+// NotEqual returns a != b
+func NotEqual(a, b interface{}) (Serie, error) {
+	return comparison(a, b, func(cmp int) bool { return cmp != Eq })
+}
+
+// Less returns a < b
+func Less(a, b interface{}) (Serie, error) {
+	return comparison(a, b, func(cmp int) bool { return cmp == Lt })
+}
+
+// LessEqual returns a <= b
+func LessEqual(a, b interface{}) (Serie, error) {
+	return comparison(a, b, func(cmp int) bool { return cmp != Gt })
+}
+
+// Greater returns a > b
+func Greater(a, b interface{}) (Serie, error) {
+	return comparison(a, b, func(cmp int) bool { return cmp == Gt })
+}
+
+// GreaterEqual returns a >= b
+func GreaterEqual(a, b interface{}) (Serie, error) {
+	return comparison(a, b, func(cmp int) bool { return cmp != Lt })
+}
+
+// logicalValue returns the bool value, or nil
+func logicalValue(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+	err := errors.Errorf("%T is not a bool", v)
+	return nil, errors.Wrap(err, ErrNotBool.Error())
+}
+
+// logical computes an element-wise logical operation
+func logical(a, b interface{}, op func(x, y interface{}) interface{}) (Serie, error) {
+	oa, ob := newOperand(a), newOperand(b)
+	n, err := size(oa, ob)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		x, err := logicalValue(oa.at(i))
+		if err != nil {
+			return nil, errors.Wrapf(err, "at index %d", i)
+		}
+		y, err := logicalValue(ob.at(i))
+		if err != nil {
+			return nil, errors.Wrapf(err, "at index %d", i)
+		}
+		values[i] = op(x, y)
+	}
+	return BoolN(values...), nil
+}
+
+// And returns a && b
+// As in SQL, false && nil gives false.
+func And(a, b interface{}) (Serie, error) {
+	return logical(a, b, func(x, y interface{}) interface{} {
+		if x == false || y == false {
+			return false
+		}
+		if x == nil || y == nil {
+			return nil
+		}
+		return true
+	})
+}
+
+// Or returns a || b
+// As in SQL, true || nil gives true.
+func Or(a, b interface{}) (Serie, error) {
+	return logical(a, b, func(x, y interface{}) interface{} {
+		if x == true || y == true {
+			return true
+		}
+		if x == nil || y == nil {
+			return nil
+		}
+		return false
+	})
+}
+
+// Not returns !a
+func Not(a interface{}) (Serie, error) {
+	return logical(a, true, func(x, _ interface{}) interface{} {
+		if x == nil {
+			return nil
+		}
+		return !x.(bool)
+	})
+}
@@ -0,0 +1,116 @@
+package serie_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable/serie"
+)
+
+func TestArithmetic(t *testing.T) {
+	a := serie.IntN(1, 2, nil, 4)
+	b := serie.IntN(10, 20, 30, 0)
+
+	s, err := serie.Add(a, b)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, 11, 22, nil, 4)
+
+	s, err = serie.Sub(a, 1)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, 0, 1, nil, 3)
+
+	s, err = serie.Div(b, a)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, 10, 10, nil, 0)
+
+	s, err = serie.Div(a, b)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, 0, 0, nil, nil)
+
+	s, err = serie.Mod(b, 3)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, 1, 2, 0, 0)
+
+	// promotion
+	s, err = serie.Mul(a, 1.5)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, 1.5, 3.0, nil, 6.0)
+
+	s, err = serie.Add(serie.Int32(1, 2), serie.Int64(1, 2))
+	assert.NoError(t, err)
+	assertSerieEq(t, s, int64(2), int64(4))
+
+	s, err = serie.Add(serie.Int32(1, 2), 1)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, int32(2), int32(3))
+
+	s, err = serie.Div(serie.Float32(1, 3), serie.Float32(2, 0))
+	assert.NoError(t, err)
+	assertSerieEq(t, s, float32(0.5), nil)
+
+	s, err = serie.Add(serie.Float32(1), serie.Int(1))
+	assert.NoError(t, err)
+	assertSerieEq(t, s, 2.0)
+
+	_, err = serie.Add(a, serie.Int(1, 2))
+	assert.Error(t, err)
+	_, err = serie.Add(a, "teemo")
+	assert.Error(t, err)
+	_, err = serie.Add(serie.String("a"), 1)
+	assert.Error(t, err)
+}
+
+func TestComparison(t *testing.T) {
+	a := serie.IntN(1, 2, nil, 4)
+
+	s, err := serie.Equal(a, 2)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, false, true, nil, false)
+
+	s, err = serie.NotEqual(a, 2.0)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, true, false, nil, true)
+
+	s, err = serie.Less(a, serie.Float64(1.5, 1.5, 1.5, 1.5))
+	assert.NoError(t, err)
+	assertSerieEq(t, s, true, false, nil, false)
+
+	s, err = serie.LessEqual(a, 2)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, true, true, nil, false)
+
+	s, err = serie.Greater(a, 2)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, false, false, nil, true)
+
+	s, err = serie.GreaterEqual(serie.StringN("a", "b", nil), "b")
+	assert.NoError(t, err)
+	assertSerieEq(t, s, false, true, nil)
+
+	_, err = serie.Equal(a, "teemo")
+	assert.Error(t, err)
+}
+
+func TestLogical(t *testing.T) {
+	a := serie.BoolN(true, true, false, nil, nil)
+	b := serie.BoolN(true, false, nil, true, false)
+
+	s, err := serie.And(a, b)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, true, false, false, nil, false)
+
+	s, err = serie.Or(a, b)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, true, true, nil, true, nil)
+
+	s, err = serie.Not(a)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, false, false, true, nil, nil)
+
+	s, err = serie.And(a, true)
+	assert.NoError(t, err)
+	assertSerieEq(t, s, true, true, false, nil, nil)
+
+	_, err = serie.Or(a, serie.Int(1, 2, 3, 4, 5))
+	assert.Error(t, err)
+}
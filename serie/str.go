@@ -0,0 +1,255 @@
+package serie
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/datasweet/cast"
+	"github.com/pkg/errors"
+)
+
+// StringOps provides string operations on a serie, like pandas .str
+// Each operation returns a new serie: a nil value stays nil.
+type StringOps struct {
+	serie Serie
+}
+
+// Str returns the string operations of a serie
+// Made for String and StringN series, the values of other series are casted to string.
+func Str(s Serie) *StringOps {
+	return &StringOps{serie: s}
+}
+
+// each calls {fn} on each non-nil string, and appends the result to {out}
+func (o *StringOps) each(out Serie, fn func(s string) interface{}) Serie {
+	values := make([]interface{}, 0, o.serie.Len())
+	for it := o.serie.Iterator(); it.Next(); {
+		if s, ok := asNonNilString(it.Current()); ok {
+			values = append(values, fn(s))
+		} else {
+			values = append(values, nil)
+		}
+	}
+	out.Append(values...)
+	return out
+}
+
+// transform returns a StringN serie
+func (o *StringOps) transform(fn func(s string) string) Serie {
+	return o.each(StringN(), func(s string) interface{} {
+		return fn(s)
+	})
+}
+
+// test returns a BoolN serie
+func (o *StringOps) test(fn func(s string) bool) Serie {
+	return o.each(BoolN(), func(s string) interface{} {
+		return fn(s)
+	})
+}
+
+// Lower returns the values in lower case
+func (o *StringOps) Lower() Serie {
+	return o.transform(strings.ToLower)
+}
+
+// Upper returns the values in upper case
+func (o *StringOps) Upper() Serie {
+	return o.transform(strings.ToUpper)
+}
+
+// TrimSpace returns the values without leading and trailing white spaces
+func (o *StringOps) TrimSpace() Serie {
+	return o.transform(strings.TrimSpace)
+}
+
+// Trim returns the values without the leading and trailing chars in {cutset}
+func (o *StringOps) Trim(cutset string) Serie {
+	return o.transform(func(s string) string {
+		return strings.Trim(s, cutset)
+	})
+}
+
+// Replace returns the values with all {old} replaced by {new}
+func (o *StringOps) Replace(old, new string) Serie {
+	return o.transform(func(s string) string {
+		return strings.ReplaceAll(s, old, new)
+	})
+}
+
+// ReplaceRegexp returns the values with the matches of {pattern} replaced by {repl}
+// {repl} can reference the capture groups, ie $1
+func (o *StringOps) ReplaceRegexp(pattern, repl string) (Serie, error) {
+	rg, err := compileRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return o.transform(func(s string) string {
+		return rg.ReplaceAllString(s, repl)
+	}), nil
+}
+
+// Match returns true if the value matches {pattern}
+func (o *StringOps) Match(pattern string) (Serie, error) {
+	rg, err := compileRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return o.test(rg.MatchString), nil
+}
+
+// Extract returns the capture {group} of the first match of {pattern}
+// The group 0 is the whole match, the value is nil if no match.
+func (o *StringOps) Extract(pattern string, group int) (Serie, error) {
+	rg, err := compileRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if group < 0 || group > rg.NumSubexp() {
+		err := errors.Errorf("group %d: pattern '%s' has %d group(s)", group, pattern, rg.NumSubexp())
+		return nil, errors.Wrap(err, ErrOutOfRange.Error())
+	}
+	return o.each(StringN(), func(s string) interface{} {
+		if m := rg.FindStringSubmatch(s); m != nil {
+			return m[group]
+		}
+		return nil
+	}), nil
+}
+
+// ExtractGroups returns a serie by capture group of {pattern}
+// The values are nil if no match.
+func (o *StringOps) ExtractGroups(pattern string) ([]Serie, error) {
+	rg, err := compileRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	n := rg.NumSubexp()
+	out := make([]Serie, n)
+	for i := range out {
+		out[i] = StringN()
+	}
+	for it := o.serie.Iterator(); it.Next(); {
+		var m []string
+		if s, ok := asNonNilString(it.Current()); ok {
+			m = rg.FindStringSubmatch(s)
+		}
+		for i := range out {
+			if m == nil {
+				out[i].Append(nil)
+			} else {
+				out[i].Append(m[i+1])
+			}
+		}
+	}
+	return out, nil
+}
+
+// Split splits the values around {sep} into {n} series
+// The last serie contains the remainder, the missing parts are nil.
+func (o *StringOps) Split(sep string, n int) []Serie {
+	if n <= 0 {
+		return nil
+	}
+	out := make([]Serie, n)
+	for i := range out {
+		out[i] = StringN()
+	}
+	for it := o.serie.Iterator(); it.Next(); {
+		var parts []string
+		if s, ok := asNonNilString(it.Current()); ok {
+			parts = strings.SplitN(s, sep, n)
+		}
+		for i := range out {
+			if i < len(parts) {
+				out[i].Append(parts[i])
+			} else {
+				out[i].Append(nil)
+			}
+		}
+	}
+	return out
+}
+
+// PadLeft pads the values on the left with {fill} up to {width} chars
+func (o *StringOps) PadLeft(width int, fill rune) Serie {
+	return o.transform(func(s string) string {
+		if n := width - utf8.RuneCountInString(s); n > 0 {
+			return strings.Repeat(string(fill), n) + s
+		}
+		return s
+	})
+}
+
+// PadRight pads the values on the right with {fill} up to {width} chars
+func (o *StringOps) PadRight(width int, fill rune) Serie {
+	return o.transform(func(s string) string {
+		if n := width - utf8.RuneCountInString(s); n > 0 {
+			return s + strings.Repeat(string(fill), n)
+		}
+		return s
+	})
+}
+
+// Substring returns the {length} chars from the char at index {start}
+// A negative {length} returns the chars up to the end.
+func (o *StringOps) Substring(start, length int) Serie {
+	return o.transform(func(s string) string {
+		runes := []rune(s)
+		if start < 0 || start >= len(runes) {
+			return ""
+		}
+		end := len(runes)
+		if length >= 0 && start+length < end {
+			end = start + length
+		}
+		return string(runes[start:end])
+	})
+}
+
+// Len returns the number of chars of the values
+func (o *StringOps) Len() Serie {
+	return o.each(IntN(), func(s string) interface{} {
+		return utf8.RuneCountInString(s)
+	})
+}
+
+// Contains returns true if the value contains {substr}
+func (o *StringOps) Contains(substr string) Serie {
+	return o.test(func(s string) bool {
+		return strings.Contains(s, substr)
+	})
+}
+
+// StartsWith returns true if the value begins with {prefix}
+func (o *StringOps) StartsWith(prefix string) Serie {
+	return o.test(func(s string) bool {
+		return strings.HasPrefix(s, prefix)
+	})
+}
+
+// EndsWith returns true if the value ends with {suffix}
+func (o *StringOps) EndsWith(suffix string) Serie {
+	return o.test(func(s string) bool {
+		return strings.HasSuffix(s, suffix)
+	})
+}
+
+// asNonNilString casts a non-nil value to string
+func asNonNilString(v interface{}) (string, bool) {
+	if v == nil {
+		return "", false
+	}
+	return cast.AsString(v)
+}
+
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	rg, err := regexp.Compile(pattern)
+	if err != nil {
+		err = errors.Wrapf(err, "pattern '%s'", pattern)
+		return nil, errors.Wrap(err, ErrInvalidPattern.Error())
+	}
+	return rg, nil
+}
@@ -0,0 +1,64 @@
+package serie_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable/serie"
+)
+
+func TestStringOps(t *testing.T) {
+	s := serie.StringN("  Teemo ", nil, "Ahri", "Jarvan IV")
+	str := serie.Str(s)
+
+	assertSerieEq(t, str.TrimSpace(), "Teemo", nil, "Ahri", "Jarvan IV")
+	assertSerieEq(t, str.Trim(" o"), "Teem", nil, "Ahri", "Jarvan IV")
+	assertSerieEq(t, str.Lower(), "  teemo ", nil, "ahri", "jarvan iv")
+	assertSerieEq(t, str.Upper(), "  TEEMO ", nil, "AHRI", "JARVAN IV")
+	assertSerieEq(t, str.Replace("e", "3"), "  T33mo ", nil, "Ahri", "Jarvan IV")
+	assertSerieEq(t, str.Len(), 8, nil, 4, 9)
+	assertSerieEq(t, str.Contains("a"), false, nil, false, true)
+	assertSerieEq(t, str.StartsWith("A"), false, nil, true, false)
+	assertSerieEq(t, str.EndsWith("IV"), false, nil, false, true)
+	assertSerieEq(t, str.Substring(1, 2), " T", nil, "hr", "ar")
+	assertSerieEq(t, str.Substring(5, -1), "mo ", nil, "", "n IV")
+
+	short := serie.Str(serie.String("7", "42", "héhé"))
+	assertSerieEq(t, short.PadLeft(3, '0'), "007", "042", "héhé")
+	assertSerieEq(t, short.PadRight(3, '.'), "7..", "42.", "héhé")
+
+	// the serie is not modified
+	assertSerieEq(t, s, "  Teemo ", nil, "Ahri", "Jarvan IV")
+}
+
+func TestStringOpsRegexp(t *testing.T) {
+	str := serie.Str(serie.StringN("a-12", "b-7", nil, "c"))
+
+	m, err := str.Match(`\d+$`)
+	assert.NoError(t, err)
+	assertSerieEq(t, m, true, true, nil, false)
+
+	m, err = str.ReplaceRegexp(`^(\w)-(\d+)$`, "$2$1")
+	assert.NoError(t, err)
+	assertSerieEq(t, m, "12a", "7b", nil, "c")
+
+	m, err = str.Extract(`(\w)-(\d+)`, 2)
+	assert.NoError(t, err)
+	assertSerieEq(t, m, "12", "7", nil, nil)
+
+	groups, err := str.ExtractGroups(`(\w)-(\d+)`)
+	assert.NoError(t, err)
+	assert.Len(t, groups, 2)
+	assertSerieEq(t, groups[0], "a", "b", nil, nil)
+	assertSerieEq(t, groups[1], "12", "7", nil, nil)
+
+	parts := str.Split("-", 2)
+	assert.Len(t, parts, 2)
+	assertSerieEq(t, parts[0], "a", "b", nil, "c")
+	assertSerieEq(t, parts[1], "12", "7", nil, nil)
+
+	_, err = str.Match("[")
+	assert.Error(t, err)
+	_, err = str.Extract(`(\w)`, 2)
+	assert.Error(t, err)
+}
@@ -0,0 +1,118 @@
+package datatable
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Union returns the distinct rows of the datatable and others, as the SQL UNION.
+// Columns are unioned by name. Columns with the same name must have the same type.
+func (t *DataTable) Union(others ...*DataTable) (*DataTable, error) {
+	for _, other := range others {
+		if other == nil {
+			continue
+		}
+		if err := checkColumnTypes(t, other); err != nil {
+			return nil, err
+		}
+	}
+
+	out, err := t.Concat(others...)
+	if err != nil {
+		return nil, err
+	}
+	return out.Distinct()
+}
+
+// Intersect returns the distinct rows of the datatable found in other, as the SQL INTERSECT.
+// Rows are compared on {cols}, all visible columns by default.
+func (t *DataTable) Intersect(other *DataTable, cols ...string) (*DataTable, error) {
+	return t.setop(other, cols, true)
+}
+
+// Except returns the distinct rows of the datatable not found in other, as the SQL EXCEPT.
+// Rows are compared on {cols}, all visible columns by default.
+func (t *DataTable) Except(other *DataTable, cols ...string) (*DataTable, error) {
+	return t.setop(other, cols, false)
+}
+
+func (t *DataTable) setop(other *DataTable, cols []string, found bool) (*DataTable, error) {
+	if other == nil {
+		return nil, ErrNilTable
+	}
+	if len(cols) == 0 {
+		cols = t.Columns()
+	}
+	if err := t.checkColumns(cols...); err != nil {
+		return nil, err
+	}
+	if err := other.checkColumns(cols...); err != nil {
+		return nil, err
+	}
+	if err := checkColumnTypes(t, other, cols...); err != nil {
+		return nil, err
+	}
+
+	ohashes, err := other.hashRows(cols)
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := t.hashRows(cols)
+	if err != nil {
+		return nil, err
+	}
+
+	series := t.columnSeries(cols)
+	keys := duplicateKeys(hashes, rowsEqual(series, series))
+	matches := matchRows(hashes, ohashes, rowsEqual(series, other.columnSeries(cols)))
+
+	rows := make([]int, 0, len(hashes))
+	for i, key := range keys {
+		if key == i && (matches[i] >= 0) == found {
+			rows = append(rows, i)
+		}
+	}
+
+	return t.pick(rows...), nil
+}
+
+// matchRows returns the index of an equal row in {ohashes} for each row in {hashes}, -1 if none
+// The rows with the same hash are compared with {equal}, a hash collision is not a match.
+func matchRows(hashes, ohashes []uint64, equal func(i, j int) bool) []int {
+	candidates := make(map[uint64][]int, len(ohashes))
+	for j, hash := range ohashes {
+		candidates[hash] = append(candidates[hash], j)
+	}
+
+	matches := make([]int, len(hashes))
+	for i, hash := range hashes {
+		matches[i] = -1
+		for _, j := range candidates[hash] {
+			if equal(i, j) {
+				matches[i] = j
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// checkColumnTypes checks if the columns with the same name have the same type.
+// If {cols} is empty, all columns are checked.
+func checkColumnTypes(left, right *DataTable, cols ...string) error {
+	if len(cols) == 0 {
+		for _, col := range left.cols {
+			cols = append(cols, col.name)
+		}
+	}
+	for _, name := range cols {
+		lc, rc := left.Column(name), right.Column(name)
+		if lc == nil || rc == nil {
+			continue
+		}
+		if lc.Type() != rc.Type() {
+			err := errors.Errorf("column '%s' has type '%s' in '%s' and '%s' in '%s'", name, lc.Type(), left.name, rc.Type(), right.name)
+			return errors.Wrap(err, ErrColumnTypeMismatch.Error())
+		}
+	}
+	return nil
+}
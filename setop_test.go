@@ -0,0 +1,73 @@
+package datatable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func TestUnion(t *testing.T) {
+	a, b, _ := sampleForConcat(t)
+
+	dt, err := a.Union(b)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"prenom", "nom", "ville", "date_naissance", "total_achat",
+		"Léon", "Dupuis", "Paris", time.Date(1983, time.March, 6, 0, 0, 0, 0, time.UTC), int64(135),
+		"Marie", "Bernard", "Paris", time.Date(1993, time.July, 3, 0, 0, 0, 0, time.UTC), int64(75),
+		"Sophie", "Dupond", "Marseille", time.Date(1986, time.February, 22, 0, 0, 0, 0, time.UTC), int64(27),
+		"Marcel", "Martin", "Paris", time.Date(1976, time.November, 24, 0, 0, 0, 0, time.UTC), int64(39),
+		"Marion", "Leroy", "Lyon", time.Date(1982, time.October, 27, 0, 0, 0, 0, time.UTC), int64(285),
+		"Paul", "Moreau", "Lyon", time.Date(1976, time.April, 19, 0, 0, 0, 0, time.UTC), int64(133),
+	)
+
+	c := datatable.New("magasin3")
+	c.AddColumn("prenom", datatable.String)
+	c.AddColumn("total_achat", datatable.Float64)
+	_, err = a.Union(c)
+	assert.Error(t, err)
+}
+
+func TestIntersect(t *testing.T) {
+	a, b, c := sampleForConcat(t)
+
+	dt, err := a.Intersect(b)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"prenom", "nom", "ville", "date_naissance", "total_achat",
+		"Marie", "Bernard", "Paris", time.Date(1993, time.July, 3, 0, 0, 0, 0, time.UTC), int64(75),
+		"Marcel", "Martin", "Paris", time.Date(1976, time.November, 24, 0, 0, 0, 0, time.UTC), int64(39),
+	)
+
+	dt, err = b.Intersect(c, "prenom", "nom")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"prenom", "nom", "ville", "date_naissance", "total_achat",
+		"Marion", "Leroy", "Lyon", time.Date(1982, time.October, 27, 0, 0, 0, 0, time.UTC), int64(285),
+		"Marie", "Bernard", "Paris", time.Date(1993, time.July, 3, 0, 0, 0, 0, time.UTC), int64(75),
+	)
+
+	_, err = a.Intersect(c)
+	assert.Error(t, err)
+}
+
+func TestExcept(t *testing.T) {
+	a, b, c := sampleForConcat(t)
+
+	dt, err := a.Except(b)
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"prenom", "nom", "ville", "date_naissance", "total_achat",
+		"Léon", "Dupuis", "Paris", time.Date(1983, time.March, 6, 0, 0, 0, 0, time.UTC), int64(135),
+		"Sophie", "Dupond", "Marseille", time.Date(1986, time.February, 22, 0, 0, 0, 0, time.UTC), int64(27),
+	)
+
+	dt, err = a.Except(c, "prenom", "nom", "ville")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, dt.NumRows())
+
+	_, err = a.Except(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package datatable
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xinzf/datatable/serie"
+)
+
+// SplitColumn splits the values of the column around {sep} into new string columns
+// The last column contains the remainder, the missing parts are nil.
+func (t *DataTable) SplitColumn(name, sep string, into []string) error {
+	col, err := t.stringSource(name, into)
+	if err != nil {
+		return err
+	}
+	return t.addStringColumns(into, serie.Str(col.serie).Split(sep, len(into)))
+}
+
+// ExtractColumn extracts the capture groups of {regex} into new string columns
+// There must be a column by capture group, the values are nil if no match.
+func (t *DataTable) ExtractColumn(name, regex string, into []string) error {
+	col, err := t.stringSource(name, into)
+	if err != nil {
+		return err
+	}
+
+	series, err := serie.Str(col.serie).ExtractGroups(regex)
+	if err != nil {
+		return err
+	}
+	if len(series) != len(into) {
+		err := errors.Errorf("regex '%s' has %d group(s) for %d column(s)", regex, len(series), len(into))
+		return errors.Wrap(err, ErrLengthMismatch.Error())
+	}
+	return t.addStringColumns(into, series)
+}
+
+// stringSource checks the source column and the new columns
+func (t *DataTable) stringSource(name string, into []string) (*column, error) {
+	pos := t.ColumnIndex(name)
+	if pos < 0 {
+		err := errors.Errorf("column '%s' not found", name)
+		return nil, errors.Wrap(err, ErrColumnNotFound.Error())
+	}
+	if len(into) == 0 {
+		err := errors.New("you must provided the new column names")
+		return nil, errors.Wrap(err, ErrNilColumnName.Error())
+	}
+	seen := make(map[string]bool, len(into))
+	for _, c := range into {
+		c = strings.TrimSpace(c)
+		if len(c) == 0 {
+			return nil, ErrNilColumnName
+		}
+		if seen[c] || t.ColumnIndex(c) >= 0 {
+			err := errors.Errorf("column '%s' already exists", c)
+			return nil, errors.Wrap(err, ErrColumnAlreadyExists.Error())
+		}
+		seen[c] = true
+	}
+	if err := t.evaluateExpressions(); err != nil {
+		return nil, err
+	}
+	return t.cols[pos], nil
+}
+
+func (t *DataTable) addStringColumns(names []string, series []serie.Serie) error {
+	for i, name := range names {
+		if err := t.addColumn(&column{
+			name:  strings.TrimSpace(name),
+			typ:   String,
+			serie: series[i],
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package datatable_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func TestSplitColumn(t *testing.T) {
+	tb := datatable.New("test")
+	tb.AddColumn("name", datatable.String, datatable.Values("Aimée Marechal", "Esmée Lefort de Lyon", nil, "Luc"))
+
+	assert.NoError(t, tb.SplitColumn("name", " ", []string{"first", "last"}))
+	checkTable(t, tb,
+		"name", "first", "last",
+		"Aimée Marechal", "Aimée", "Marechal",
+		"Esmée Lefort de Lyon", "Esmée", "Lefort de Lyon",
+		nil, nil, nil,
+		"Luc", "Luc", nil,
+	)
+
+	assert.Error(t, tb.SplitColumn("unknown", " ", []string{"a"}))
+	assert.Error(t, tb.SplitColumn("name", " ", []string{"first"}))
+	assert.Error(t, tb.SplitColumn("name", " ", []string{"a", "a"}))
+	assert.Error(t, tb.SplitColumn("name", " ", nil))
+}
+
+func TestExtractColumn(t *testing.T) {
+	tb := datatable.New("test")
+	tb.AddColumn("email", datatable.String, datatable.Values("aime.marechal@example.com", "unknown", "lucrolland@example.org"))
+
+	assert.NoError(t, tb.ExtractColumn("email", `^([^@]+)@(.+)$`, []string{"user", "domain"}))
+	checkTable(t, tb,
+		"email", "user", "domain",
+		"aime.marechal@example.com", "aime.marechal", "example.com",
+		"unknown", nil, nil,
+		"lucrolland@example.org", "lucrolland", "example.org",
+	)
+
+	assert.Error(t, tb.ExtractColumn("email", `^([^@]+)@`, []string{"a", "b"}))
+	assert.Error(t, tb.ExtractColumn("email", `[`, []string{"a"}))
+}
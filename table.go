@@ -3,6 +3,8 @@ package datatable
 import (
 	"fmt"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // New creates a new datatable
@@ -12,11 +14,13 @@ func New(name string) *DataTable {
 
 // DataTable is our main struct
 type DataTable struct {
-	name    string
-	cols    []*column
-	nrows   int
-	dirty   bool
-	hasExpr bool
+	name      string
+	cols      []*column
+	nrows     int
+	dirty     bool
+	dirtyRows map[int]bool
+	dirtyCols map[string]bool
+	hasExpr   bool
 }
 
 // Name returns the datatable's name
@@ -93,15 +97,37 @@ func (t *DataTable) ColumnIndex(name string) int {
 	return -1
 }
 
+// checkColumns checks if all columns exist in datatable
+func (t *DataTable) checkColumns(names ...string) error {
+	for _, name := range names {
+		if t.ColumnIndex(name) < 0 {
+			err := errors.Errorf("column '%s' not found", name)
+			return errors.Wrap(err, ErrColumnNotFound.Error())
+		}
+	}
+	return nil
+}
+
 // Records returns the rows in datatable as string
 // Computes all expressions.
+// <!> Records panics if an expression can't be evaluated, see RecordsE
 func (t *DataTable) Records() [][]string {
+	rows, err := t.RecordsE()
+	if err != nil {
+		panic(err)
+	}
+	return rows
+}
+
+// RecordsE returns the rows in datatable as string
+// Computes all expressions.
+func (t *DataTable) RecordsE() ([][]string, error) {
 	if t == nil {
-		return nil
+		return nil, nil
 	}
 
 	if err := t.evaluateExpressions(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	// visible columns
@@ -120,19 +146,30 @@ func (t *DataTable) Records() [][]string {
 		}
 		rows = append(rows, r)
 	}
-	return rows
+	return rows, nil
 }
 
 // Rows returns the rows in datatable
 // Computes all expressions.
+// <!> Rows panics if an expression can't be evaluated, see RowsE
 func (t *DataTable) Rows(opt ...ExportOption) []Row {
+	rows, err := t.RowsE(opt...)
+	if err != nil {
+		panic(err)
+	}
+	return rows
+}
+
+// RowsE returns the rows in datatable
+// Computes all expressions.
+func (t *DataTable) RowsE(opt ...ExportOption) ([]Row, error) {
 	if t == nil {
-		return nil
+		return nil, nil
 	}
 
 	opts := newExportOptions(opt...)
 	if err := t.evaluateExpressions(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	// visible columns
@@ -151,7 +188,7 @@ func (t *DataTable) Rows(opt ...ExportOption) []Row {
 		}
 		rows = append(rows, r)
 	}
-	return rows
+	return rows, nil
 }
 
 func (t *DataTable) String() string {
@@ -161,14 +198,35 @@ func (t *DataTable) String() string {
 }
 
 // Row gets the row at index
+// Computes all expressions.
+// Row returns an empty row if the index is out of range or an expression can't be evaluated.
+//
+// Deprecated: use RowE, which returns the error.
 func (t *DataTable) Row(at int, opt ...ExportOption) Row {
+	r, err := t.RowE(at, opt...)
+	if err != nil {
+		return make(Row)
+	}
+	return r
+}
+
+// RowE gets the row at index
+// Computes all expressions.
+func (t *DataTable) RowE(at int, opt ...ExportOption) (Row, error) {
+	if err := t.checkRowIndex(at); err != nil {
+		return nil, err
+	}
+
 	opts := newExportOptions(opt...)
-	t.evaluateExpressions()
+	if err := t.evaluateExpressions(); err != nil {
+		return nil, err
+	}
+
 	r := make(Row, len(t.cols))
 	for _, col := range t.cols {
 		if opts.WithHiddenCols || col.IsVisible() {
 			r[col.name] = col.serie.Get(at)
 		}
 	}
-	return r
+	return r, nil
 }
@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"time"
 
 	"github.com/xinzf/datatable"
 	"github.com/xinzf/datatable/import/csv"
+	"github.com/xinzf/datatable/serie"
 )
 
 func main() {
@@ -29,16 +29,9 @@ func main() {
 	fmt.Println(dt2)
 
 	groups, err := dt.GroupBy(datatable.GroupBy{
-		Name: "year",
-		Type: datatable.Int,
-		Keyer: func(row datatable.Row) (interface{}, bool) {
-			if d, ok := row["date"]; ok {
-				if tm, ok := d.(time.Time); ok {
-					return tm.Year(), true
-				}
-			}
-			return nil, false
-		},
+		Name:  "year",
+		Type:  datatable.Int,
+		Keyer: datatable.TimeKeyer("date", serie.Year),
 	})
 	if err != nil {
 		log.Fatalf("GROUP BY 'year': %v", err)
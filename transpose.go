@@ -0,0 +1,61 @@
+package datatable
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Transpose turns the rows into columns.
+// The values of {headerCol} become the column names and the names of the other
+// visible columns become the values of the first column, named {headerCol}.
+// The new columns have the common type of the transposed columns (or raw).
+func (dt *DataTable) Transpose(headerCol string) (*DataTable, error) {
+	if err := dt.checkColumns(headerCol); err != nil {
+		return nil, err
+	}
+	if err := dt.evaluateExpressions(); err != nil {
+		return nil, err
+	}
+
+	var cols []*column
+	var types []ColumnType
+	for _, col := range dt.cols {
+		if col.IsVisible() && col.name != headerCol {
+			cols = append(cols, col)
+			types = append(types, col.typ)
+		}
+	}
+	typ := commonColumnType(types...)
+
+	// create columns
+	out := New(dt.name)
+	names := make([]interface{}, 0, len(cols))
+	for _, col := range cols {
+		names = append(names, col.name)
+	}
+	if err := out.AddColumn(headerCol, String, Values(names...)); err != nil {
+		err = errors.Wrapf(err, "can't add column '%s'", headerCol)
+		return nil, errors.Wrap(err, ErrCantAddColumn.Error())
+	}
+
+	header := dt.Column(headerCol).Serie()
+	for i := 0; i < dt.nrows; i++ {
+		h := header.Get(i)
+		if h == nil {
+			err := errors.Errorf("row #%d has no header", i)
+			return nil, errors.Wrap(err, ErrNilColumnName.Error())
+		}
+		values := make([]interface{}, 0, len(cols))
+		for _, col := range cols {
+			values = append(values, col.serie.Get(i))
+		}
+		name := fmt.Sprint(h)
+		if err := out.AddColumn(name, typ, Values(values...)); err != nil {
+			err = errors.Wrapf(err, "can't add column '%s'", name)
+			return nil, errors.Wrap(err, ErrCantAddColumn.Error())
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,47 @@
+package datatable_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xinzf/datatable"
+)
+
+func TestTranspose(t *testing.T) {
+	dt := datatable.New("summary")
+	dt.AddColumn("metric", datatable.String, datatable.Values("sessions", "bounces"))
+	dt.AddColumn("jan", datatable.Int, datatable.Values(120, 30))
+	dt.AddColumn("feb", datatable.Int64, datatable.Values(150, nil))
+
+	out, err := dt.Transpose("metric")
+	assert.NoError(t, err)
+	assert.Equal(t, datatable.Int64, out.Column("sessions").Type())
+	checkTable(t, out,
+		"metric", "sessions", "bounces",
+		"jan", int64(120), int64(30),
+		"feb", int64(150), nil,
+	)
+
+	// back
+	back, err := out.Transpose("metric")
+	assert.NoError(t, err)
+	checkTable(t, back,
+		"metric", "jan", "feb",
+		"sessions", int64(120), int64(150),
+		"bounces", int64(30), nil,
+	)
+
+	// mixed types
+	dt.AddColumn("comment", datatable.String, datatable.Values("good", "bad"))
+	out, err = dt.Transpose("metric")
+	assert.NoError(t, err)
+	assert.Equal(t, datatable.Raw, out.Column("sessions").Type())
+
+	// duplicated header
+	dt.AppendRow("sessions", 1, 2, "dup")
+	_, err = dt.Transpose("metric")
+	assert.Error(t, err)
+
+	_, err = dt.Transpose("unknown")
+	assert.Error(t, err)
+}
@@ -1,13 +1,30 @@
 package datatable
 
+import (
+	"strings"
+
+	"github.com/datasweet/expr"
+	"github.com/pkg/errors"
+)
+
 // Where filters the datatable based on a predicate
+// <!> Where panics if an expression can't be evaluated, see WhereE
 func (t *DataTable) Where(predicate func(row Row) bool) *DataTable {
+	cpy, err := t.WhereE(predicate)
+	if err != nil {
+		panic(err)
+	}
+	return cpy
+}
+
+// WhereE filters the datatable based on a predicate
+func (t *DataTable) WhereE(predicate func(row Row) bool) (*DataTable, error) {
 	if predicate == nil {
-		return t.EmptyCopy()
+		return t.EmptyCopy(), nil
 	}
 
 	if err := t.evaluateExpressions(); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	subset := make([]int, 0, t.nrows) // max
@@ -22,16 +39,65 @@ func (t *DataTable) Where(predicate func(row Row) bool) *DataTable {
 		}
 	}
 
-	cpy := t.EmptyCopy()
+	return t.pick(subset...), nil
+}
 
-	if len(subset) == 0 {
-		return cpy
+// WhereExpr filters the datatable with a boolean expression, ie "`win` > 10 && `champ` != \"Teemo\""
+// The expression is evaluated on the columns with the engine of the computed columns.
+// An empty expression selects all rows.
+func (t *DataTable) WhereExpr(formulae string) (*DataTable, error) {
+	formulae = strings.TrimSpace(formulae)
+	if len(formulae) == 0 {
+		return t.Copy(), nil
 	}
 
-	cpy.nrows = len(subset)
-	for i, col := range t.cols {
-		cpy.cols[i].serie = col.serie.Pick(subset...)
+	parsed, err := expr.Parse(formulae)
+	if err != nil {
+		err = errors.Wrapf(err, "where expr '%s'", formulae)
+		return nil, errors.Wrap(err, ErrFormulaeSyntax.Error())
+	}
+	if err := checkFunctions(parsed); err != nil {
+		return nil, errors.Wrapf(err, "where expr '%s'", formulae)
+	}
+	if err := t.checkFunctionColumns(parsed); err != nil {
+		return nil, errors.Wrapf(err, "where expr '%s'", formulae)
+	}
+	for _, ref := range exprRefs(parsed) {
+		if t.ColumnIndex(ref) < 0 {
+			err := errors.Errorf("where expr '%s' references unknown column '%s'", formulae, ref)
+			return nil, errors.Wrap(err, ErrUnknownColumnRef.Error())
+		}
 	}
 
-	return cpy
+	arr, err := t.evalNode(parsed, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "where expr '%s'", formulae)
+	}
+
+	subset, err := whereRows(arr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "where expr '%s'", formulae)
+	}
+
+	return t.pick(subset...), nil
+}
+
+// whereRows returns the index of the rows with a true value
+// nil values are considered as false
+func whereRows(values []interface{}) ([]int, error) {
+	subset := make([]int, 0, len(values)) // max
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		b, ok := v.(bool)
+		if !ok {
+			err := errors.Errorf("expected a boolean, got %T at row %d", v, i)
+			return nil, errors.Wrap(err, ErrEvaluateExpr.Error())
+		}
+		if b {
+			subset = append(subset, i)
+		}
+	}
+	return subset, nil
 }
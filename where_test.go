@@ -38,3 +38,48 @@ func TestWhere(t *testing.T) {
 		3, "Marine", "Prevost", "m.prevost@example.com", "Lille", time.Date(2013, time.February, 21, 0, 0, 0, 0, time.UTC), "A00106", 235.35,
 	)
 }
+
+func TestWhereExpr(t *testing.T) {
+	tb := datatable.New("test")
+	tb.AddColumn("champ", datatable.String, datatable.Values("Malzahar", "Xerath", "Teemo", "Ahri"))
+	tb.AddColumn("win", datatable.Int, datatable.Values(10, 20, 666, 5))
+	tb.AddColumn("double", datatable.Int, datatable.Expr("`win` * 2"))
+
+	dt, err := tb.WhereExpr("`win` > 10 && `champ` != \"Teemo\"")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"champ", "win", "double",
+		"Xerath", 20, 40,
+	)
+
+	dt, err = tb.WhereExpr("`double` >= 40")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"champ", "win", "double",
+		"Xerath", 20, 40,
+		"Teemo", 666, 1332,
+	)
+
+	dt, err = tb.WhereExpr("`win` > avg(`win`)")
+	assert.NoError(t, err)
+	checkTable(t, dt,
+		"champ", "win", "double",
+		"Teemo", 666, 1332,
+	)
+
+	dt, err = tb.WhereExpr("  ")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, dt.NumRows())
+
+	dt, err = tb.WhereExpr("`win` > ")
+	assert.Error(t, err)
+	assert.Nil(t, dt)
+
+	dt, err = tb.WhereExpr("`loose` > 10")
+	assert.Error(t, err)
+	assert.Nil(t, dt)
+
+	dt, err = tb.WhereExpr("`win` + 1")
+	assert.Error(t, err)
+	assert.Nil(t, dt)
+}